@@ -2,7 +2,7 @@ package assets
 
 import (
 	"bytes"
-	_ "embed"
+	"io/fs"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -26,31 +26,38 @@ var OrangeExhaustAnimation [4]SpriteSheet
 var GreenExhaustAnimation [4]SpriteSheet
 
 var Bullet *ebiten.Image
+var Asteroid *ebiten.Image
+var Shield *ebiten.Image
+
+var ShieldPowerUp *ebiten.Image
+var RapidFirePowerUp *ebiten.Image
+var SpeedBoostPowerUp *ebiten.Image
+var ShotgunPowerUp *ebiten.Image
+var MissilePowerUp *ebiten.Image
 
 var OrangeExplosion SpriteSheet
 
-//go:embed sfx/explosion.wav
 var Explosion []byte
-
-//go:embed sfx/laser.wav
 var LaserAudio []byte
-
-//go:embed sfx/BattleMusic.mp3
 var BattleMusic []byte
-
-//go:embed sfx/IntroMusic.mp3
 var IntroMusic []byte
-
-//go:embed sfx/hit.wav
 var Hit []byte
+var Thruster []byte
 
 func init() {
-	projectileImage := mustLoadImageFromBytes(projectile)
+	Explosion = mustReadFile("sfx/explosion.wav")
+	LaserAudio = mustReadFile("sfx/laser.wav")
+	BattleMusic = mustReadFile("sfx/BattleMusic.mp3")
+	IntroMusic = mustReadFile("sfx/IntroMusic.mp3")
+	Hit = mustReadFile("sfx/hit.wav")
+	Thruster = mustReadFile("sfx/thruster.wav")
+
+	projectileImage := mustLoadImage("SpaceShooterAssetPack/Projectiles.png")
 	projectile := NewSprite(projectileImage, 8, 8)
 
-	iu := mustLoadImageFromBytes(iu)
-	Background = NewSprite(mustLoadImageFromBytes(background), 512, 512)
-	Ships = NewSprite(mustLoadImageFromBytes(ships), 8, 8)
+	iu := mustLoadImage("SpaceShooterAssetPack/IU.png")
+	Background = NewSprite(mustLoadImage("background.png"), 512, 512)
+	Ships = NewSprite(mustLoadImage("SpaceShooterAssetPack/Ships.png"), 8, 8)
 
 	Borders = NewSprite(iu, 16, 16)
 	Arrows = NewSprite(iu, 8, 8)
@@ -58,12 +65,20 @@ func init() {
 	Healthbar = NewSprite(iu, 16, 8)
 	Messagebar = NewSprite(projectileImage, 24, 8)
 
-	MunroNarrow = mustLoadFontFromBytes(munroNarrow)
-	Munro = mustLoadFontFromBytes(munro)
+	MunroNarrow = mustLoadFont("MunroFont/munro-narrow.ttf")
+	Munro = mustLoadFont("MunroFont/munro.ttf")
 
-	Miscellaneous := NewSprite(mustLoadImageFromBytes(miscellaneous), 8, 8)
+	Miscellaneous := NewSprite(mustLoadImage("SpaceShooterAssetPack/Miscellaneous.png"), 8, 8)
 
 	Bullet = projectile.GetTile(TileIndex{X: 3, Y: 6})
+	Asteroid = Miscellaneous.GetTile(TileIndex{X: 1, Y: 3})
+	Shield = Miscellaneous.GetTiles(TileIndex{X: 9, Y: 4}, 2, 2)
+
+	ShieldPowerUp = Miscellaneous.GetTile(TileIndex{X: 3, Y: 0})
+	RapidFirePowerUp = Miscellaneous.GetTile(TileIndex{X: 3, Y: 1})
+	SpeedBoostPowerUp = Miscellaneous.GetTile(TileIndex{X: 2, Y: 1})
+	ShotgunPowerUp = Miscellaneous.GetTile(TileIndex{X: 2, Y: 0})
+	MissilePowerUp = Miscellaneous.GetTile(TileIndex{X: 1, Y: 0})
 
 	for i := range 4 {
 		OrangeExhaustAnimation[i] = NewSpriteSheet(
@@ -91,28 +106,16 @@ func init() {
 	)
 }
 
-//go:embed SpaceShooterAssetPack/Miscellaneous.png
-var miscellaneous []byte
-
-//go:embed MunroFont/munro-narrow.ttf
-var munroNarrow []byte
-
-//go:embed MunroFont/munro.ttf
-var munro []byte
-
-//go:embed background.png
-var background []byte
-
-//go:embed SpaceShooterAssetPack/Ships.png
-var ships []byte
-
-//go:embed SpaceShooterAssetPack/IU.png
-var iu []byte
-
-//go:embed SpaceShooterAssetPack/Projectiles.png
-var projectile []byte
+func mustReadFile(name string) []byte {
+	data, err := fs.ReadFile(files, name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
 
-func mustLoadImageFromBytes(data []byte) *ebiten.Image {
+func mustLoadImage(name string) *ebiten.Image {
+	data := mustReadFile(name)
 	image, _, err := ebitenutil.NewImageFromReader(bytes.NewReader(data))
 	if err != nil {
 		panic(err)
@@ -120,7 +123,8 @@ func mustLoadImageFromBytes(data []byte) *ebiten.Image {
 	return image
 }
 
-func mustLoadFontFromBytes(data []byte) *text.GoTextFaceSource {
+func mustLoadFont(name string) *text.GoTextFaceSource {
+	data := mustReadFile(name)
 	fontSource, err := text.NewGoTextFaceSource(bytes.NewReader(data))
 	if err != nil {
 		panic(err)
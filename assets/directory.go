@@ -0,0 +1,10 @@
+//go:build assetsdir
+
+package assets
+
+import "os"
+
+// Built with `-tags assetsdir`, the assets are read from the assets directory
+// on startup instead, so they can be edited without rebuilding. The game has
+// to be run from the root of the repository.
+var files = os.DirFS("assets")
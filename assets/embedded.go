@@ -0,0 +1,13 @@
+//go:build !assetsdir
+
+package assets
+
+import "embed"
+
+// The assets are compiled into the binary, so the game runs from anywhere.
+//
+//go:embed sfx/explosion.wav sfx/laser.wav sfx/BattleMusic.mp3 sfx/IntroMusic.mp3 sfx/hit.wav sfx/thruster.wav
+//go:embed SpaceShooterAssetPack/Miscellaneous.png SpaceShooterAssetPack/Ships.png SpaceShooterAssetPack/IU.png SpaceShooterAssetPack/Projectiles.png
+//go:embed MunroFont/munro-narrow.ttf MunroFont/munro.ttf
+//go:embed background.png
+var files embed.FS
@@ -32,3 +32,16 @@ func (self *Sprite) GetTile(tile TileIndex) *ebiten.Image {
 
 	return self.Image.SubImage(rect).(*ebiten.Image)
 }
+
+// Returns the region spanning several tiles starting from the given tile.
+func (self *Sprite) GetTiles(tile TileIndex, columns int, rows int) *ebiten.Image {
+	x0 := tile.X * self.TileWidth
+	y0 := tile.Y * self.TileHeight
+
+	x1 := (tile.X + columns) * self.TileWidth
+	y1 := (tile.Y + rows) * self.TileHeight
+
+	rect := image.Rect(x0, y0, x1, y1)
+
+	return self.Image.SubImage(rect).(*ebiten.Image)
+}
@@ -1,10 +1,16 @@
 package client
 
 import (
+	"astro-blasters/assets"
 	"astro-blasters/client/config"
+	"astro-blasters/client/replay"
 	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/arena"
 	"astro-blasters/client/scenes/common/failure"
+	"astro-blasters/client/scenes/connecting"
 	"astro-blasters/client/scenes/menu"
+	"astro-blasters/client/scenes/transition"
+	"astro-blasters/server/messages"
 	"bytes"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -21,6 +27,9 @@ type App struct {
 
 	player *audio.Player
 
+	// Created the first time the ship thrusts, then paused and resumed.
+	thruster *audio.Player
+
 	audioContext *audio.Context
 }
 
@@ -31,10 +40,22 @@ func NewApp(config *config.ClientConfig) *App {
 	}
 
 	app.controller = scenes.NewAppController(app)
-	app.controller.ChangeScene(menu.NewMenuScene(config))
+	app.controller.ChangeScene(app.firstScene())
 	return app
 }
 
+func (self *App) firstScene() scenes.Scene {
+	if self.config.ReplayPath == "" {
+		return menu.NewMenuScene(self.config)
+	}
+
+	recording, err := replay.Load(self.config.ReplayPath)
+	if err != nil {
+		return failure.NewFailureScene(self.config, err)
+	}
+	return arena.NewReplayScene(self.config, recording)
+}
+
 func (self *App) Run() error {
 	ebiten.SetWindowSize(self.config.ScreenWidth, self.config.ScreenHeight)
 	ebiten.SetWindowTitle("Astro Blasters")
@@ -44,6 +65,10 @@ func (self *App) Run() error {
 }
 
 func (self *App) Update() error {
+	if fade, ok := self.scene.(*transition.FadeTransition); ok && fade.IsDone() {
+		self.scene = fade.Scene()
+	}
+
 	self.scene.Update(self.controller)
 	return nil
 }
@@ -52,11 +77,26 @@ func (self *App) Draw(screen *ebiten.Image) {
 	self.scene.Draw(screen)
 }
 
+// Everything is drawn on a screen of the logical size, which Ebiten scales to
+// fit the window whatever its size or pixel density.
 func (self *App) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
 	return self.config.ScreenWidth, self.config.ScreenHeight
 }
 
 func (self *App) ChangeScene(scene scenes.Scene) {
+	if self.scene != nil {
+		// Keep the last frame of the old scene around to fade it out.
+		if self.config.FadeDuration > 0 {
+			last := ebiten.NewImage(self.config.ScreenWidth, self.config.ScreenHeight)
+			self.scene.Draw(last)
+			scene = transition.NewFadeTransition(last, scene, self.config.FadeDuration)
+		}
+		self.scene.Dispose()
+	}
+
+	// Only the arena keeps the thruster going.
+	self.PlayThruster(false)
+
 	if err := scene.Configure(self.controller); err != nil {
 		self.scene = failure.NewFailureScene(self.config, err)
 		return
@@ -65,6 +105,14 @@ func (self *App) ChangeScene(scene scenes.Scene) {
 	self.scene = scene
 }
 
+func (self *App) ReturnToMenu() {
+	self.ChangeScene(menu.NewMenuScene(self.config))
+}
+
+func (self *App) ReturnToLobby(handshake messages.ConnectionHandshake) {
+	self.ChangeScene(connecting.NewConnectingScene(self.config, handshake))
+}
+
 func (self *App) ChangeMusic(data []byte) {
 	if self.player != nil && self.player.IsPlaying() {
 		self.player.Close()
@@ -82,6 +130,7 @@ func (self *App) ChangeMusic(data []byte) {
 		panic(err)
 	}
 
+	self.player.SetVolume(self.config.Volume)
 	self.player.Play()
 }
 
@@ -91,5 +140,34 @@ func (self *App) PlaySfx(data []byte) {
 		panic(err)
 	}
 	player, err := self.audioContext.NewPlayer(stream)
+	if err != nil {
+		panic(err)
+	}
+
+	player.SetVolume(self.config.Volume)
 	player.Play()
 }
+
+func (self *App) PlayThruster(isThrusting bool) {
+	if self.thruster == nil {
+		if !isThrusting {
+			return
+		}
+
+		stream, err := wav.DecodeWithoutResampling(bytes.NewReader(assets.Thruster))
+		if err != nil {
+			panic(err)
+		}
+		self.thruster, err = self.audioContext.NewPlayer(audio.NewInfiniteLoop(stream, stream.Length()))
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if isThrusting && !self.thruster.IsPlaying() {
+		self.thruster.SetVolume(self.config.Volume)
+		self.thruster.Play()
+	} else if !isThrusting && self.thruster.IsPlaying() {
+		self.thruster.Pause()
+	}
+}
@@ -1,8 +1,73 @@
 package config
 
+import (
+	"astro-blasters/client/network"
+	"log/slog"
+	"time"
+)
+
+// The height of the screen the arena was laid out for, taller screens draw it
+// larger so that they show the same part of the map.
+const ReferenceScreenHeight = 720
+
 type ClientConfig struct {
+	// The logical size of the screen, which is stretched to fit the window.
 	ScreenWidth  int
 	ScreenHeight int
 
+	// How much larger than its native size the arena is drawn, 0 to derive it
+	// from the height of the screen.
+	RenderScale float64
+
 	ServerWebsocketURL string
+
+	// Accepts any certificate from a wss:// server, for development servers
+	// with self-signed certificates. Browsers ignore it.
+	InsecureSkipVerify bool
+
+	// How quickly remote ships glide towards their corrected positions each
+	// frame, from 0 (never) to 1 (snap immediately).
+	InterpolationFactor float64
+
+	KeyBindings KeyBindings
+
+	// Master volume applied to the music and sound effects, from 0 to 1.
+	Volume float64
+
+	// Fires towards the mouse cursor instead of the heading of the ship.
+	AimAtCursor bool
+
+	// Where every match played is recorded to, the file is overwritten by
+	// each new match. Empty to not record anything.
+	RecordPath string
+
+	// A recording played back on startup instead of opening the menu.
+	ReplayPath string
+
+	// How long switching from one scene to another fades through black, 0
+	// to switch instantly.
+	FadeDuration time.Duration
+
+	// Where the client reports its connection to the server, network errors
+	// that are otherwise only counted are logged at the debug level.
+	Logger *slog.Logger
+}
+
+// Returns how much larger than its native size the arena is drawn.
+func (self *ClientConfig) WorldScale() float64 {
+	if self.RenderScale > 0 {
+		return self.RenderScale
+	}
+	return float64(self.ScreenHeight) / ReferenceScreenHeight
+}
+
+// Returns the size of the part of the arena that fits on the screen.
+func (self *ClientConfig) ViewSize() (float64, float64) {
+	scale := self.WorldScale()
+	return float64(self.ScreenWidth) / scale, float64(self.ScreenHeight) / scale
+}
+
+// Returns where the server is and how to reach it.
+func (self *ClientConfig) Endpoint() network.Endpoint {
+	return network.Endpoint{URL: self.ServerWebsocketURL, InsecureSkipVerify: self.InsecureSkipVerify}
 }
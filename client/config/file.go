@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
+)
+
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		ScreenWidth:         1080,
+		ScreenHeight:        720,
+		ServerWebsocketURL:  "ws://localhost:8080/play/ws",
+		InterpolationFactor: 0.2,
+		KeyBindings:         DefaultKeyBindings(),
+		Volume:              1,
+		FadeDuration:        400 * time.Millisecond,
+		Logger:              slog.Default(),
+	}
+}
+
+// The settings as written in a config file, the ones left out keep their
+// current value.
+type clientFile struct {
+	ServerWebsocketURL  *string
+	InsecureSkipVerify  *bool
+	ScreenWidth         *int
+	ScreenHeight        *int
+	RenderScale         *float64
+	InterpolationFactor *float64
+	KeyBindings         *KeyBindings
+	Volume              *float64
+	AimAtCursor         *bool
+	RecordPath          *string
+
+	// Given as a string such as "400ms".
+	FadeDuration *string
+}
+
+// Reads the settings from a JSON file on top of the config, e.g.
+// `{"Volume": 0.5, "KeyBindings": {"Fire": ["J"]}}`. Actions missing from the
+// key bindings keep their keys.
+func LoadClientConfig(path string, config *ClientConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read the config from %s: %w", path, err)
+	}
+
+	var file clientFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("Failed to parse the config from %s: %w", path, err)
+	}
+
+	set(&config.ServerWebsocketURL, file.ServerWebsocketURL)
+	set(&config.InsecureSkipVerify, file.InsecureSkipVerify)
+	set(&config.ScreenWidth, file.ScreenWidth)
+	set(&config.ScreenHeight, file.ScreenHeight)
+	set(&config.RenderScale, file.RenderScale)
+	set(&config.InterpolationFactor, file.InterpolationFactor)
+	set(&config.Volume, file.Volume)
+	set(&config.AimAtCursor, file.AimAtCursor)
+	set(&config.RecordPath, file.RecordPath)
+	if file.FadeDuration != nil {
+		duration, err := time.ParseDuration(*file.FadeDuration)
+		if err != nil {
+			return fmt.Errorf("Invalid FadeDuration in %s: %w", path, err)
+		}
+		config.FadeDuration = duration
+	}
+	if file.KeyBindings != nil {
+		config.KeyBindings = mergeKeyBindings(config.KeyBindings, *file.KeyBindings)
+	}
+	return nil
+}
+
+// Returns why the config cannot be used, if it can't.
+func (self *ClientConfig) Validate() error {
+	serverUrl, err := url.Parse(self.ServerWebsocketURL)
+	if err != nil {
+		return fmt.Errorf("Invalid server URL %q: %w", self.ServerWebsocketURL, err)
+	}
+	if serverUrl.Scheme != "ws" && serverUrl.Scheme != "wss" {
+		return fmt.Errorf("Invalid server URL %q, it should start with ws:// or wss://, such as ws://localhost:8080/play/ws", self.ServerWebsocketURL)
+	}
+	if serverUrl.Host == "" {
+		return fmt.Errorf("Invalid server URL %q, it has no host", self.ServerWebsocketURL)
+	}
+
+	if self.ScreenWidth <= 0 || self.ScreenHeight <= 0 {
+		return fmt.Errorf("Invalid screen size %dx%d, both sides should be positive", self.ScreenWidth, self.ScreenHeight)
+	}
+	if self.RenderScale < 0 {
+		return fmt.Errorf("Invalid render scale %g, it should be 0 or more", self.RenderScale)
+	}
+	if self.InterpolationFactor <= 0 || self.InterpolationFactor > 1 {
+		return fmt.Errorf("Invalid interpolation factor %g, it should be above 0 and at most 1", self.InterpolationFactor)
+	}
+	if self.Volume < 0 || self.Volume > 1 {
+		return fmt.Errorf("Invalid volume %g, it should be from 0 to 1", self.Volume)
+	}
+	if self.FadeDuration < 0 {
+		return fmt.Errorf("Invalid fade duration %s, it should be 0 or more", self.FadeDuration)
+	}
+	return nil
+}
+
+// Overwrites the value with the one from the file, if it was given.
+func set[T any](value *T, loaded *T) {
+	if loaded != nil {
+		*value = *loaded
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The keys bound to each action, any of them triggers the action.
+type KeyBindings struct {
+	Forward                []ebiten.Key
+	RotateClockwise        []ebiten.Key
+	RotateCounterClockwise []ebiten.Key
+	Fire                   []ebiten.Key
+	Boost                  []ebiten.Key
+}
+
+func DefaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		Forward:                []ebiten.Key{ebiten.KeyW, ebiten.KeyUp},
+		RotateClockwise:        []ebiten.Key{ebiten.KeyD, ebiten.KeyRight},
+		RotateCounterClockwise: []ebiten.Key{ebiten.KeyA, ebiten.KeyLeft},
+		Fire:                   []ebiten.Key{ebiten.KeySpace},
+		Boost:                  []ebiten.Key{ebiten.KeyShiftLeft},
+	}
+}
+
+// Reads the key bindings from a JSON file where every action maps to a list
+// of key names, e.g. `{"Fire": ["Space", "J"]}`. Actions missing from the
+// file keep their default keys.
+func LoadKeyBindings(path string) (KeyBindings, error) {
+	bindings := DefaultKeyBindings()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bindings, fmt.Errorf("Failed to read key bindings from %s: %w", path, err)
+	}
+
+	var loaded KeyBindings
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return bindings, fmt.Errorf("Failed to parse key bindings from %s: %w", path, err)
+	}
+
+	return mergeKeyBindings(bindings, loaded), nil
+}
+
+// Replaces the keys of the actions bound in `loaded`, the others keep theirs.
+func mergeKeyBindings(bindings KeyBindings, loaded KeyBindings) KeyBindings {
+	if len(loaded.Forward) > 0 {
+		bindings.Forward = loaded.Forward
+	}
+	if len(loaded.RotateClockwise) > 0 {
+		bindings.RotateClockwise = loaded.RotateClockwise
+	}
+	if len(loaded.RotateCounterClockwise) > 0 {
+		bindings.RotateCounterClockwise = loaded.RotateCounterClockwise
+	}
+	if len(loaded.Fire) > 0 {
+		bindings.Fire = loaded.Fire
+	}
+	if len(loaded.Boost) > 0 {
+		bindings.Boost = loaded.Boost
+	}
+	return bindings
+}
@@ -0,0 +1,24 @@
+//go:build !wasm
+
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	"github.com/coder/websocket"
+)
+
+func dial(ctx context.Context, endpoint Endpoint) (*websocket.Conn, error) {
+	opts := &websocket.DialOptions{}
+	if endpoint.InsecureSkipVerify && strings.HasPrefix(endpoint.URL, "wss://") {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		opts.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	connection, _, err := websocket.Dial(ctx, endpoint.URL, opts)
+	return connection, err
+}
@@ -0,0 +1,160 @@
+package network
+
+import (
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+const (
+	// How many times we try to reach the server before giving up.
+	maxConnectionAttempts = 5
+
+	// The time we wait after the first failed attempt, doubled after every
+	// subsequent failure.
+	initialBackoff = 250 * time.Millisecond
+
+	// The time given to a single attempt to dial and complete the handshake.
+	attemptTimeout = time.Second
+)
+
+// Where the server is and how to reach it.
+type Endpoint struct {
+	URL string
+
+	// Accepts any certificate from a wss:// server, such as the self-signed
+	// ones of development servers.
+	InsecureSkipVerify bool
+}
+
+// Returned when the server refused to let us in, trying again won't help.
+type RejectedError struct {
+	Reason string
+}
+
+func (self *RejectedError) Error() string {
+	return self.Reason
+}
+
+// Returned when every ship on the server is taken, there may be room again
+// later.
+type ServerFullError struct {
+	MaxPlayers int
+}
+
+func (self *ServerFullError) Error() string {
+	return fmt.Sprintf("The server is full, all %d ships are taken", self.MaxPlayers)
+}
+
+// Dials the server and performs the connection handshake, retrying with an
+// exponential backoff whenever the server cannot be reached.
+func Connect(ctx context.Context, endpoint Endpoint, handshake messages.ConnectionHandshake) (*websocket.Conn, messages.ConnectionHandshakeResponse, error) {
+	backoff := initialBackoff
+	handshake.ProtocolVersion = rpc.ProtocolVersion
+	handshake.SupportsCompression = true
+
+	var err error
+	for attempt := 1; attempt <= maxConnectionAttempts; attempt++ {
+		var connection *websocket.Conn
+		var response messages.ConnectionHandshakeResponse
+
+		connection, response, err = connect(ctx, endpoint, handshake)
+		if err == nil {
+			return connection, response, nil
+		}
+
+		var rejected *RejectedError
+		var full *ServerFullError
+		if attempt == maxConnectionAttempts || errors.As(err, &rejected) || errors.As(err, &full) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, messages.ConnectionHandshakeResponse{}, ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+
+	return nil, messages.ConnectionHandshakeResponse{}, err
+}
+
+func connect(ctx context.Context, endpoint Endpoint, handshake messages.ConnectionHandshake) (*websocket.Conn, messages.ConnectionHandshakeResponse, error) {
+	var response messages.ConnectionHandshakeResponse
+	url := endpoint.URL
+
+	ctx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	defer cancel()
+
+	connection, err := dial(ctx, endpoint)
+	if err != nil {
+		return nil, response, fmt.Errorf("Failed to connect to the server at %s", url)
+	}
+
+	if err := rpc.WriteMessage(ctx, connection, rpc.NewBaseMessage(handshake)); err != nil {
+		connection.CloseNow()
+		return nil, response, fmt.Errorf("Failed to send handshake to the server at %s", url)
+	}
+
+	var message rpc.BaseMessage
+	if err := rpc.ReceiveMessage(ctx, connection, &message); err != nil {
+		connection.CloseNow()
+		return nil, response, fmt.Errorf("Error receiving handshake response: %s", err.Error())
+	}
+
+	if message.MessageType == "ConnectionRejected" {
+		connection.CloseNow()
+
+		var rejected messages.ConnectionRejected
+		if err := rpc.DecodeExpectedMessage(message, &rejected); err != nil {
+			return nil, response, fmt.Errorf("The server rejected the connection")
+		}
+		return nil, response, &RejectedError{Reason: rejected.Reason}
+	}
+
+	if message.MessageType == "ServerFull" {
+		connection.CloseNow()
+
+		var full messages.ServerFull
+		if err := rpc.DecodeExpectedMessage(message, &full); err != nil {
+			return nil, response, fmt.Errorf("The server is full")
+		}
+		return nil, response, &ServerFullError{MaxPlayers: full.MaxPlayers}
+	}
+
+	if err := rpc.DecodeExpectedMessage(message, &response); err != nil {
+		connection.CloseNow()
+		return nil, response, fmt.Errorf("Error decoding handshake response: %s", err.Error())
+	}
+
+	return connection, response, nil
+}
+
+// Asks the server for the best players of all time without joining the game.
+func FetchLeaderboard(ctx context.Context, endpoint Endpoint) ([]messages.PlayerStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	defer cancel()
+
+	url := endpoint.URL
+	connection, err := dial(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to the server at %s", url)
+	}
+	defer connection.CloseNow()
+
+	if err := rpc.WriteMessage(ctx, connection, rpc.NewBaseMessage(messages.RequestLeaderboard{})); err != nil {
+		return nil, fmt.Errorf("Failed to ask the server at %s for the leaderboard", url)
+	}
+
+	var leaderboard messages.LeaderboardData
+	if err := rpc.ReceiveExpectedMessage(ctx, connection, &leaderboard); err != nil {
+		return nil, fmt.Errorf("Error receiving the leaderboard: %s", err.Error())
+	}
+	return leaderboard.Entries, nil
+}
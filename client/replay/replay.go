@@ -0,0 +1,118 @@
+package replay
+
+import (
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Starts every replay file, replays only play back on the protocol they were
+// recorded with.
+type header struct {
+	ProtocolVersion int
+	Response        messages.ConnectionHandshakeResponse
+}
+
+// A message received from the server, along with when it arrived.
+type Entry struct {
+	Elapsed time.Duration
+	Message rpc.BaseMessage
+}
+
+// A recorded match, the state of the world when the recording started
+// followed by every message received after that.
+type Replay struct {
+	Response messages.ConnectionHandshakeResponse
+	Entries  []Entry
+}
+
+// Writes the messages received during a match to a file as they arrive.
+type Recorder struct {
+	mutex   sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *msgpack.Encoder
+	started time.Time
+}
+
+// Creates the replay file, starting it with the state of the world sent by
+// the server in the handshake.
+func NewRecorder(path string, response messages.ConnectionHandshakeResponse) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the replay at %s: %w", path, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	recorder := &Recorder{
+		file:    file,
+		writer:  writer,
+		encoder: msgpack.NewEncoder(writer),
+		started: time.Now(),
+	}
+	if err := recorder.encoder.Encode(header{ProtocolVersion: rpc.ProtocolVersion, Response: response}); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("Failed to write the replay at %s: %w", path, err)
+	}
+	return recorder, nil
+}
+
+func (self *Recorder) Record(message rpc.BaseMessage) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.encoder.Encode(Entry{Elapsed: time.Since(self.started), Message: message})
+}
+
+func (self *Recorder) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if err := self.writer.Flush(); err != nil {
+		self.file.Close()
+		return err
+	}
+	return self.file.Close()
+}
+
+// Reads a whole replay file.
+func Load(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the replay at %s: %w", path, err)
+	}
+	defer file.Close()
+
+	decoder := msgpack.NewDecoder(bufio.NewReader(file))
+
+	var start header
+	if err := decoder.Decode(&start); err != nil {
+		return nil, fmt.Errorf("Failed to read the replay at %s: %w", path, err)
+	}
+	if start.ProtocolVersion != rpc.ProtocolVersion {
+		return nil, fmt.Errorf("The replay at %s was recorded with protocol v%d but the game uses v%d", path, start.ProtocolVersion, rpc.ProtocolVersion)
+	}
+
+	replay := &Replay{Response: start.Response}
+	for {
+		var entry Entry
+		err := decoder.Decode(&entry)
+		// A recording cut short by a crash still plays up to where it stops.
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read the replay at %s: %w", path, err)
+		}
+		replay.Entries = append(replay.Entries, entry)
+	}
+	return replay, nil
+}
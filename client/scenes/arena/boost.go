@@ -0,0 +1,49 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	boostBarWidth  = 120
+	boostBarHeight = 8
+	boostBarMargin = 20
+)
+
+var (
+	boostReadyColor    = color.RGBA{90, 200, 255, 230}
+	boostChargingColor = color.RGBA{90, 120, 150, 200}
+)
+
+// Draws at the bottom of the screen how far the boost has recharged.
+func (self *ArenaScene) drawBoostCooldown(screen *ebiten.Image) {
+	if self.player == nil || self.isSpectating() {
+		return
+	}
+
+	remaining := game.BoostCooldownRemaining(component.Player.Get(self.player))
+	charge := 1 - float32(remaining)/float32(game.BoostCooldown)
+
+	x := float32(self.config.ScreenWidth-boostBarWidth) / 2
+	y := float32(self.config.ScreenHeight - boostBarMargin - boostBarHeight)
+
+	barColor := boostChargingColor
+	if remaining == 0 {
+		barColor = boostReadyColor
+	}
+	vector.DrawFilledRect(screen, x, y, boostBarWidth, boostBarHeight, color.RGBA{0, 0, 0, 150}, false)
+	vector.DrawFilledRect(screen, x, y, boostBarWidth*charge, boostBarHeight, barColor, false)
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 20}
+	width, _ := text.Measure("Boost", font, 12)
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(self.config.ScreenWidth)/2-width/2, float64(y)-24)
+	text.Draw(screen, "Boost", font, opts)
+}
@@ -0,0 +1,66 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// How close to an edge of the map the ship has to be for the warning to
+	// show up, it gets stronger the closer the ship gets.
+	boundaryWarningDistance = 300
+
+	// The width of the glow on the edge of the screen, drawn as bands that
+	// fade towards the center.
+	boundaryWarningWidth = 48
+	boundaryWarningBands = 8
+)
+
+// Tints the edges of the screen facing the edges of the map our ship is
+// about to hit, or about to wrap around.
+func (self *ArenaScene) drawBoundaryWarning(screen *ebiten.Image) {
+	if self.player == nil || !self.isAlive {
+		return
+	}
+
+	position := component.Position.Get(self.player)
+	width := float32(self.config.ScreenWidth)
+	height := float32(self.config.ScreenHeight)
+
+	// The strength of the warning on each side, from 0 to 1.
+	left := proximity(position.X)
+	right := proximity(self.simulation.MapWidth - position.X)
+	top := proximity(position.Y)
+	bottom := proximity(self.simulation.MapHeight - position.Y)
+
+	band := float32(boundaryWarningWidth) / boundaryWarningBands
+	for i := range boundaryWarningBands {
+		offset := float32(i) * band
+		fade := 1 - float32(i)/boundaryWarningBands
+
+		if left > 0 {
+			vector.DrawFilledRect(screen, offset, 0, band, height, warningColor(left*fade), false)
+		}
+		if right > 0 {
+			vector.DrawFilledRect(screen, width-offset-band, 0, band, height, warningColor(right*fade), false)
+		}
+		if top > 0 {
+			vector.DrawFilledRect(screen, 0, offset, width, band, warningColor(top*fade), false)
+		}
+		if bottom > 0 {
+			vector.DrawFilledRect(screen, 0, height-offset-band, width, band, warningColor(bottom*fade), false)
+		}
+	}
+}
+
+func proximity(distance float64) float32 {
+	return float32(max(0, 1-distance/boundaryWarningDistance))
+}
+
+func warningColor(strength float32) color.Color {
+	alpha := uint8(120 * strength)
+	return color.RGBA{alpha, 0, 0, alpha}
+}
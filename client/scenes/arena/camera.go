@@ -6,9 +6,16 @@ import (
 	"math"
 )
 
+const (
+	// How far spectators can zoom out and in.
+	MinCameraZoom = 0.5
+	MaxCameraZoom = 2.0
+)
+
 type Camera struct {
 	X           float64
 	Y           float64
+	Zoom        float64
 	SceneWidth  float64
 	SceneHeight float64
 	config      *config.ClientConfig
@@ -18,21 +25,39 @@ func NewCamera(x, y, sceneWidth, sceneHeight float64, config *config.ClientConfi
 	return &Camera{
 		X:           x,
 		Y:           y,
+		Zoom:        1,
 		SceneWidth:  sceneWidth,
 		SceneHeight: sceneHeight,
 		config:      config,
 	}
 }
 
+// Returns the size of the part of the arena the camera sees.
+func (self *Camera) ViewSize() (float64, float64) {
+	width, height := self.config.ViewSize()
+	return width / self.Zoom, height / self.Zoom
+}
+
+// Returns how much the arena is scaled up when drawn to the screen.
+func (self *Camera) Scale() float64 {
+	return self.config.WorldScale() * self.Zoom
+}
+
+func (self *Camera) SetZoom(zoom float64) {
+	self.Zoom = math.Max(MinCameraZoom, math.Min(zoom, MaxCameraZoom))
+}
+
 func (self *Camera) FocusTarget(target component.PositionData) {
-	self.X = -target.X + float64(self.config.ScreenWidth)/2.0
-	self.Y = -target.Y + float64(self.config.ScreenHeight)/2.0
+	width, height := self.ViewSize()
+	self.X = -target.X + width/2.0
+	self.Y = -target.Y + height/2.0
 }
 
 func (self *Camera) Constrain() {
+	width, height := self.ViewSize()
 	self.X = math.Min(self.X, 0)
 	self.Y = math.Min(self.Y, 0)
 
-	self.X = math.Max(self.X, -float64(self.SceneWidth)+float64(self.config.ScreenWidth))
-	self.Y = math.Max(self.Y, -float64(self.SceneHeight)+float64(self.config.ScreenHeight))
+	self.X = math.Max(self.X, -float64(self.SceneWidth)+width)
+	self.Y = math.Max(self.Y, -float64(self.SceneHeight)+height)
 }
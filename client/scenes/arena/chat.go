@@ -0,0 +1,107 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	maxChatEntries  = 6
+	chatEntryExpiry = 10 * time.Second
+)
+
+type chatEntry struct {
+	playerName string
+	text       string
+	receivedAt time.Time
+}
+
+type Chat struct {
+	// Guards the entries since they are appended while receiving messages
+	// from the server.
+	mutex   sync.Mutex
+	entries []chatEntry
+
+	isTyping bool
+	input    string
+}
+
+// Handles the keyboard while chatting, returns the message to be sent once
+// the player presses enter.
+func (self *Chat) Update() (message string, send bool) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		if !self.isTyping {
+			self.isTyping = true
+			return "", false
+		}
+
+		message = self.input
+		self.isTyping = false
+		self.input = ""
+		return message, message != ""
+	}
+
+	if !self.isTyping {
+		return "", false
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		self.isTyping = false
+		self.input = ""
+		return "", false
+	}
+
+	self.input += string(ebiten.AppendInputChars(nil))
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(self.input) > 0 {
+		runes := []rune(self.input)
+		self.input = string(runes[:len(runes)-1])
+	}
+
+	return "", false
+}
+
+func (self *Chat) Add(playerName string, text string) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.entries = append(self.entries, chatEntry{playerName: playerName, text: text, receivedAt: time.Now()})
+	if len(self.entries) > maxChatEntries {
+		self.entries = self.entries[len(self.entries)-maxChatEntries:]
+	}
+}
+
+// Draws the recent messages in the bottom left corner of the screen, and the
+// message being typed below them.
+func (self *Chat) Draw(screen *ebiten.Image) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 18}
+	y := float64(screen.Bounds().Dy()) - 40
+
+	if self.isTyping {
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(10, y)
+		text.Draw(screen, fmt.Sprintf("> %s_", self.input), font, opts)
+	}
+
+	for i := len(self.entries) - 1; i >= 0; i-- {
+		entry := self.entries[i]
+		if !self.isTyping && time.Since(entry.receivedAt) > chatEntryExpiry {
+			continue
+		}
+
+		y -= 22
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(10, y)
+		opts.ColorScale.ScaleAlpha(0.9)
+		text.Draw(screen, fmt.Sprintf("%s: %s", entry.playerName, entry.text), font, opts)
+	}
+}
@@ -0,0 +1,56 @@
+package arena
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// The length of the arms of the crosshair and the gap at its center.
+	crosshairSize = 12
+	crosshairGap  = 4
+)
+
+var crosshairColor = color.RGBA{255, 255, 255, 220}
+
+// Whether we are flying our ship with the mouse, the system cursor is then
+// replaced by a crosshair.
+func (self *ArenaScene) isAimingWithCursor() bool {
+	return self.config.AimAtCursor && self.replay == nil && !self.isPaused && !self.isQuitting && !self.isSpectating()
+}
+
+// Hides the system cursor while we aim with it, and shows it again whenever
+// we stop, such as when pausing or opening the quit prompt.
+func (self *ArenaScene) updateCursor() {
+	isHidden := self.isAimingWithCursor()
+	if isHidden == self.isCursorHidden {
+		return
+	}
+
+	self.isCursorHidden = isHidden
+	if isHidden {
+		ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	} else {
+		ebiten.SetCursorMode(ebiten.CursorModeVisible)
+	}
+}
+
+func (self *ArenaScene) drawCrosshair(screen *ebiten.Image) {
+	if !self.isCursorHidden {
+		return
+	}
+
+	cursorX, cursorY := ebiten.CursorPosition()
+	x, y := float32(cursorX), float32(cursorY)
+
+	for _, arm := range [][2]float32{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		x0 := x + arm[0]*crosshairGap
+		y0 := y + arm[1]*crosshairGap
+		x1 := x + arm[0]*crosshairSize
+		y1 := y + arm[1]*crosshairSize
+		vector.StrokeLine(screen, x0, y0, x1, y1, 2, crosshairColor, true)
+	}
+	vector.DrawFilledCircle(screen, x, y, 1.5, crosshairColor, true)
+}
@@ -3,7 +3,10 @@ package arena
 import (
 	"astro-blasters/assets"
 	"astro-blasters/client/config"
+	"fmt"
 	"image/color"
+	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -16,12 +19,28 @@ const (
 type DeathScene struct {
 	fadeInAlpha float64
 	config      *config.ClientConfig
+	subtitle    string
+
+	// When the server brings the ship back, counted down in the subtitle.
+	// Zero while no respawn is coming.
+	respawnWhen time.Time
+}
+
+func NewDeathScene(config *config.ClientConfig, respawnWhen time.Time) *DeathScene {
+	return &DeathScene{
+		fadeInAlpha: 0,
+		config:      config,
+		subtitle:    "you will be respawned",
+		respawnWhen: respawnWhen,
+	}
 }
 
-func NewDeathScene(config *config.ClientConfig) *DeathScene {
+// Shown instead when the player ran out of lives in an elimination game.
+func NewEliminationScene(config *config.ClientConfig) *DeathScene {
 	return &DeathScene{
 		fadeInAlpha: 0,
 		config:      config,
+		subtitle:    "you are out, press tab to spectate",
 	}
 }
 
@@ -55,7 +74,11 @@ func (self *DeathScene) Draw(screen *ebiten.Image) {
 
 	{
 		font := text.GoTextFace{Source: assets.Munro, Size: 50}
-		message := "you will be respawned"
+		message := self.subtitle
+		if !self.respawnWhen.IsZero() {
+			remaining := math.Ceil(max(time.Until(self.respawnWhen), 0).Seconds())
+			message = fmt.Sprintf("%s in %d", self.subtitle, int(remaining))
+		}
 		width, height := text.Measure(message, &font, 12)
 
 		opts := &text.DrawOptions{}
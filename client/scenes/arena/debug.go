@@ -0,0 +1,68 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// How often we ping the server to measure the round trip time and to let
+	// it know we are still around.
+	pingInterval = time.Second
+
+	// Without a pong for this long the connection is considered dead.
+	pongTimeout = 5 * time.Second
+)
+
+func (self *ArenaScene) pingServer() {
+	if self.replay != nil || time.Since(self.lastPing) < pingInterval {
+		return
+	}
+
+	// Dropping the connection makes the receiver reconnect.
+	if time.Since(self.lastPong) > pongTimeout {
+		self.lastPong = time.Now()
+		self.connection.CloseNow()
+	}
+
+	self.networkStats.mutex.Lock()
+	roundTripTime := self.networkStats.roundTripTime
+	self.networkStats.mutex.Unlock()
+
+	self.lastPing = time.Now()
+	self.sendMessage(rpc.NewBaseMessage(messages.Ping{SentAt: self.lastPing.UnixNano(), RoundTripTime: roundTripTime}))
+}
+
+// Draws the performance and network stats in the top right corner, toggled
+// with F3.
+func (self *ArenaScene) drawDebugOverlay(screen *ebiten.Image) {
+	stats := &self.networkStats
+	stats.mutex.Lock()
+	lines := []string{
+		fmt.Sprintf("FPS %.0f  TPS %.0f", ebiten.ActualFPS(), ebiten.ActualTPS()),
+		fmt.Sprintf("Ping %d ms", stats.roundTripTime.Milliseconds()),
+		fmt.Sprintf("Entities %d", self.simulation.ECS.World.Len()),
+		fmt.Sprintf("Messages In %d/s  Out %d/s", stats.messageInPS, stats.messageOutPS),
+	}
+	stats.mutex.Unlock()
+	lines = append(lines, fmt.Sprintf("Dropped %d", stats.droppedMessages()))
+
+	const width, lineHeight = 260, 20
+	x := float32(self.config.ScreenWidth - width - 10)
+	vector.DrawFilledRect(screen, x, 10, width, float32(len(lines)*lineHeight+10), color.RGBA{0, 0, 0, 170}, false)
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 16}
+	for i, line := range lines {
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(float64(x)+10, float64(15+i*lineHeight))
+		text.Draw(screen, line, font, opts)
+	}
+}
@@ -0,0 +1,50 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/game/component"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Whether we ran out of lives in an elimination game, we then spectate until
+// the match is over.
+func (self *ArenaScene) isEliminated() bool {
+	return self.lives > 0 && self.player != nil && component.Player.Get(self.player).Lives == 0
+}
+
+// Returns how many ships still have lives left.
+func (self *ArenaScene) countSurvivors() int {
+	survivors := 0
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		if playerData.IsConnected && playerData.Lives > 0 {
+			survivors++
+		}
+	}
+	return survivors
+}
+
+// Draws how many players are left and how many lives we have below the match
+// timer, only in elimination games.
+func (self *ArenaScene) drawEliminationStatus(screen *ebiten.Image) {
+	if self.lives == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("%d Players Left", self.countSurvivors())
+	if self.player != nil {
+		message += fmt.Sprintf(" - %d Lives", component.Player.Get(self.player).Lives)
+	}
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 24}
+	width, _ := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(self.config.ScreenWidth)/2-width/2, 90)
+	text.Draw(screen, message, font, opts)
+}
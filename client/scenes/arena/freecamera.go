@@ -0,0 +1,76 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// How fast the free camera flies at normal zoom, in pixels per second.
+	freeCameraSpeed = 900
+
+	// How much one notch of the scroll wheel zooms in or out.
+	freeCameraZoomStep = 1.1
+)
+
+// Lets spectators zoom with the scroll wheel and fly the camera around with
+// WASD, which stops following the spectated ship.
+func (self *ArenaScene) handleFreeCameraInput() {
+	if _, wheelY := ebiten.Wheel(); wheelY != 0 {
+		self.camera.SetZoom(self.camera.Zoom * math.Pow(freeCameraZoomStep, wheelY))
+	}
+
+	if self.chat.isTyping {
+		return
+	}
+
+	dx, dy := 0.0, 0.0
+	if ebiten.IsKeyPressed(ebiten.KeyW) {
+		dy -= 1
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyS) {
+		dy += 1
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyA) {
+		dx -= 1
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyD) {
+		dx += 1
+	}
+
+	if !self.isFreeCamera {
+		if dx == 0 && dy == 0 {
+			return
+		}
+
+		// Take off from wherever the camera is looking.
+		width, height := self.camera.ViewSize()
+		self.freeCamera = component.PositionData{X: -self.camera.X + width/2, Y: -self.camera.Y + height/2}
+		self.isFreeCamera = true
+		self.spectatedId = types.InvalidPlayerId
+	}
+
+	if dx != 0 || dy != 0 {
+		step := freeCameraSpeed / self.camera.Zoom / float64(ebiten.TPS())
+		length := math.Hypot(dx, dy)
+		self.freeCamera.X += dx / length * step
+		self.freeCamera.Y += dy / length * step
+	}
+
+	// The camera stops at the edges of the map, so the free camera does too
+	// rather than having to fly back before it moves again.
+	width, height := self.camera.ViewSize()
+	self.freeCamera.X = clampToView(self.freeCamera.X, width, self.simulation.MapWidth)
+	self.freeCamera.Y = clampToView(self.freeCamera.Y, height, self.simulation.MapHeight)
+}
+
+// Keeps a view of the given size centered on `center` inside the map.
+func clampToView(center, size, mapSize float64) float64 {
+	if size >= mapSize {
+		return mapSize / 2
+	}
+	return math.Max(size/2, math.Min(center, mapSize-size/2))
+}
@@ -0,0 +1,190 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/scenes"
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"math"
+	"time"
+)
+
+// Registers how each message sent by the server updates the arena.
+func (self *ArenaScene) newDispatcher(controller *scenes.AppController) *rpc.Dispatcher {
+	dispatcher := rpc.NewDispatcher()
+
+	rpc.Register(dispatcher, func(message messages.UpdatePosition) {
+		player := self.simulation.FindCorrespondingPlayer(message.PlayerId)
+		if player == nil {
+			return
+		}
+
+		if player == self.player {
+			self.prediction.reconcile(message.Sequence, message.Position, component.Position.Get(player))
+			return
+		}
+		self.correctPlayerPosition(player, message.Position)
+	})
+	rpc.Register(dispatcher, self.applyFullSnapshot)
+	rpc.Register(dispatcher, self.applyDeltaSnapshot)
+
+	rpc.Register(dispatcher, func(event messages.EventPlayerConnected) {
+		// The player might already be known from a world snapshot.
+		if self.simulation.FindCorrespondingPlayer(event.PlayerId) != nil {
+			return
+		}
+		self.createRemotePlayer(event.PlayerId, &event.Position, event.PlayerName, true, event.Team, event.Class)
+	})
+	rpc.Register(dispatcher, func(event messages.EventPlayerDisconnected) {
+		if player := self.simulation.FindCorrespondingPlayer(event.PlayerId); player != nil {
+			self.simulation.QueueRemoval(player)
+		}
+
+		// Our own ship goes away once we join the spectators.
+		if event.PlayerId == self.playerId {
+			self.player = nil
+		}
+	})
+	rpc.Register(dispatcher, func(event messages.EventPlayerMove) {
+		self.simulation.RegisterPlayerMove(event.PlayerId, event.Move)
+	})
+	rpc.Register(dispatcher, func(event messages.EventPlayerSteering) {
+		self.simulation.RegisterPlayerSteering(event.PlayerId, event.IsSteering, event.Angle)
+	})
+
+	rpc.Register(dispatcher, func(event messages.EventGamePaused) {
+		self.isPaused = event.IsPaused
+	})
+	rpc.Register(dispatcher, func(event messages.MatchTimer) {
+		self.matchEndsWhen = time.Now().Add(event.Remaining)
+	})
+	rpc.Register(dispatcher, func(event messages.MatchEnded) {
+		if event.NextRoundIn > 0 {
+			self.matchEndsWhen = time.Time{}
+			self.nextRoundWhen = time.Now().Add(event.NextRoundIn)
+			return
+		}
+
+		// Only the first results count, we leave the arena for them.
+		select {
+		case self.matchResults <- event.PlayerData:
+		default:
+		}
+	})
+	rpc.Register(dispatcher, func(event messages.RoundReset) {
+		self.resetRound(event.PlayerData)
+	})
+
+	rpc.Register(dispatcher, func(event messages.EventPlayerHit) {
+		player := self.simulation.FindCorrespondingPlayer(event.PlayerId)
+		if player != nil {
+			playerData := component.Player.Get(player)
+			playerData.HitFlashExpiresWhen = time.Now().Add(hitFlashDuration)
+
+			// Harder hits shake the camera harder.
+			damage := playerData.Health + playerData.Shield - event.Health - event.Shield
+			intensity := 10 * math.Max(damage, 0) / game.PlayerDamagePerHit
+			if event.PlayerId == self.playerId {
+				self.startShake(10, intensity)
+			} else {
+				self.startShakeAt(component.Position.Get(player), 10, intensity/2)
+			}
+		}
+
+		self.simulation.UpdatePlayerHealth(event.PlayerId, event.Health)
+		self.simulation.UpdatePlayerShield(event.PlayerId, event.Shield)
+		controller.PlaySfx(assets.Hit)
+	})
+	rpc.Register(dispatcher, func(event messages.EventPlayerDied) {
+		killed := self.simulation.FindCorrespondingPlayer(event.PlayerId)
+		if killed == nil {
+			return
+		}
+		killer := self.simulation.FindCorrespondingPlayer(event.KilledBy)
+
+		killerName := ""
+		if killer != nil {
+			killerName = component.Player.Get(killer).Name
+		}
+		self.killFeed.Add(killerName, component.Player.Get(killed).Name)
+		self.startShakeAt(component.Position.Get(killed), 20, 15)
+		component.Player.Get(killed).Lives = event.Lives
+
+		self.simulation.RegisterPlayerDeath(killed, killer)
+		if event.PlayerId == self.playerId {
+			self.deathScene = NewDeathScene(self.config, time.Now().Add(game.RespawnDelay))
+			if self.isEliminated() {
+				self.deathScene = NewEliminationScene(self.config)
+			}
+			self.isAlive = false
+		}
+		controller.PlaySfx(assets.Explosion)
+	})
+	rpc.Register(dispatcher, func(event messages.EventPlayerFireBullet) {
+		player := self.simulation.FindCorrespondingPlayer(event.PlayerId)
+		if player == nil {
+			return
+		}
+		self.simulation.FireShot(player, game.Shot{
+			Angle:     event.Angle,
+			TargetId:  event.TargetId,
+			MissileId: event.MissileId,
+		})
+		controller.PlaySfx(assets.LaserAudio)
+	})
+	rpc.Register(dispatcher, func(event messages.EventPlayerRespawned) {
+		player := self.simulation.FindCorrespondingPlayer(event.PlayerId)
+		if player == nil {
+			return
+		}
+
+		self.simulation.RespawnPlayer(player, event.Position)
+		if event.PlayerId == self.playerId {
+			self.isAlive = true
+		}
+	})
+
+	rpc.Register(dispatcher, func(message messages.SpawnAsteroid) {
+		asteroid := message.Asteroid
+		if self.simulation.FindCorrespondingAsteroid(asteroid.AsteroidId) == nil {
+			self.simulation.CreateAsteroid(asteroid.AsteroidId, &asteroid.Position, asteroid.Radius)
+		}
+	})
+	rpc.Register(dispatcher, func(event messages.EventAsteroidDestroyed) {
+		// Our own simulation may have already seen the collision.
+		if asteroid := self.simulation.FindCorrespondingAsteroid(event.AsteroidId); asteroid != nil {
+			self.simulation.QueueRemoval(asteroid)
+		}
+	})
+
+	rpc.Register(dispatcher, func(message messages.SpawnPowerUp) {
+		powerUp := message.PowerUp
+		if self.simulation.FindCorrespondingPowerUp(powerUp.PowerUpId) == nil {
+			self.simulation.CreatePowerUp(powerUp.PowerUpId, &powerUp.Position, powerUp.Kind)
+		}
+	})
+	rpc.Register(dispatcher, func(message messages.PowerUpCollected) {
+		// Our own simulation may have already removed it.
+		if powerUp := self.simulation.FindCorrespondingPowerUp(message.PowerUpId); powerUp != nil {
+			self.simulation.QueueRemoval(powerUp)
+		}
+		if player := self.simulation.FindCorrespondingPlayer(message.PlayerId); player != nil {
+			self.simulation.ApplyPowerUp(player, message.Kind)
+		}
+	})
+
+	rpc.Register(dispatcher, func(message messages.Pong) {
+		self.lastPong = time.Now()
+		self.networkStats.recordRoundTrip(time.Unix(0, message.SentAt))
+	})
+
+	rpc.Register(dispatcher, func(event messages.EventChatMessage) {
+		if player := self.simulation.FindCorrespondingPlayer(event.PlayerId); player != nil {
+			self.chat.Add(component.Player.Get(player).Name, event.Text)
+		}
+	})
+
+	return dispatcher
+}
@@ -0,0 +1,104 @@
+package arena
+
+import (
+	"astro-blasters/client/config"
+	"astro-blasters/game/component"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// How far an analog stick has to be pushed before it counts as input.
+const gamepadDeadZone = 0.3
+
+// How far the left stick has to be pushed for the ship to thrust as well as
+// turn.
+const gamepadThrustZone = 0.8
+
+// The actions the player is currently holding down, gathered from every
+// input device.
+type inputState struct {
+	isMovingForward            bool
+	isRotatingClockwise        bool
+	isRotatingCounterClockwise bool
+	isFiring                   bool
+	isBoosting                 bool
+
+	// Whether an analog stick points the ship towards `steerAngle`, in
+	// radians.
+	isSteering bool
+	steerAngle float64
+}
+
+func (self inputState) merge(other inputState) inputState {
+	merged := inputState{
+		isMovingForward:            self.isMovingForward || other.isMovingForward,
+		isRotatingClockwise:        self.isRotatingClockwise || other.isRotatingClockwise,
+		isRotatingCounterClockwise: self.isRotatingCounterClockwise || other.isRotatingCounterClockwise,
+		isFiring:                   self.isFiring || other.isFiring,
+		isBoosting:                 self.isBoosting || other.isBoosting,
+		isSteering:                 self.isSteering,
+		steerAngle:                 self.steerAngle,
+	}
+
+	// The first stick that is pushed steers.
+	if !self.isSteering {
+		merged.isSteering = other.isSteering
+		merged.steerAngle = other.steerAngle
+	}
+	return merged
+}
+
+func readKeyboard(bindings config.KeyBindings) inputState {
+	return inputState{
+		isMovingForward:            isAnyKeyPressed(bindings.Forward),
+		isRotatingClockwise:        isAnyKeyPressed(bindings.RotateClockwise),
+		isRotatingCounterClockwise: isAnyKeyPressed(bindings.RotateCounterClockwise),
+		isFiring:                   isAnyKeyPressed(bindings.Fire),
+		isBoosting:                 isAnyKeyPressed(bindings.Boost),
+	}
+}
+
+// Reads every connected gamepad with a standard layout. The ship turns
+// towards wherever the left stick points, and thrusts once the stick is
+// pushed most of the way. The d-pad rotates and thrusts like the keyboard.
+// The bottom face button or the right trigger fires, and the right bumper
+// boosts.
+func readGamepads() inputState {
+	state := inputState{}
+
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+
+		horizontal := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+		vertical := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+		isPressed := func(button ebiten.StandardGamepadButton) bool {
+			return ebiten.IsStandardGamepadButtonPressed(id, button)
+		}
+
+		// Up on the stick is a heading of 0, like the ships.
+		tilt := math.Hypot(horizontal, vertical)
+		state = state.merge(inputState{
+			isMovingForward:            tilt > gamepadThrustZone || isPressed(ebiten.StandardGamepadButtonLeftTop),
+			isRotatingClockwise:        isPressed(ebiten.StandardGamepadButtonLeftRight),
+			isRotatingCounterClockwise: isPressed(ebiten.StandardGamepadButtonLeftLeft),
+			isFiring:                   isPressed(ebiten.StandardGamepadButtonRightBottom) || isPressed(ebiten.StandardGamepadButtonFrontBottomRight),
+			isBoosting:                 isPressed(ebiten.StandardGamepadButtonFrontTopRight),
+			isSteering:                 tilt > gamepadDeadZone,
+			steerAngle:                 component.WrapAngle(math.Atan2(horizontal, -vertical)),
+		})
+	}
+
+	return state
+}
+
+func isAnyKeyPressed(keys []ebiten.Key) bool {
+	for _, key := range keys {
+		if ebiten.IsKeyPressed(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	maxKillFeedEntries  = 5
+	killFeedEntryExpiry = 5 * time.Second
+
+	// How long an entry takes to fade out at the end of its life.
+	killFeedFade = time.Second
+)
+
+type killFeedEntry struct {
+	message    string
+	receivedAt time.Time
+}
+
+// The latest kills, shown in the top right corner of the screen.
+type KillFeed struct {
+	// Guards the entries since they are appended while receiving messages
+	// from the server.
+	mutex   sync.Mutex
+	entries []killFeedEntry
+}
+
+// Records a kill, the killer is empty when the victim crashed into an
+// asteroid.
+func (self *KillFeed) Add(killer string, victim string) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	message := fmt.Sprintf("%s was destroyed", victim)
+	if killer != "" {
+		message = fmt.Sprintf("%s destroyed %s", killer, victim)
+	}
+
+	self.entries = append(self.entries, killFeedEntry{message: message, receivedAt: time.Now()})
+	if len(self.entries) > maxKillFeedEntries {
+		self.entries = self.entries[len(self.entries)-maxKillFeedEntries:]
+	}
+}
+
+func (self *KillFeed) Draw(screen *ebiten.Image) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 18}
+	right := float64(screen.Bounds().Dx()) - 10
+	y := 10.0
+
+	for _, entry := range self.entries {
+		remaining := killFeedEntryExpiry - time.Since(entry.receivedAt)
+		if remaining <= 0 {
+			continue
+		}
+
+		width, _ := text.Measure(entry.message, font, 12)
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(right-width, y)
+		opts.ColorScale.ScaleAlpha(float32(min(remaining.Seconds()/killFeedFade.Seconds(), 1)))
+		text.Draw(screen, entry.message, font, opts)
+
+		y += 22
+	}
+}
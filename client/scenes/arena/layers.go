@@ -0,0 +1,93 @@
+package arena
+
+import (
+	"astro-blasters/game/types"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// The layers of the arena from back to front, everything on a layer is
+// drawn above the layers before it. The layers below the HUD are in world
+// space and are scaled along with the arena.
+const (
+	layerBackground = iota
+	layerTerrain
+	layerShips
+	layerEffects
+	layerLabels
+	layerHud
+	layerOverlay
+)
+
+type renderer struct {
+	layer int
+	draw  func(screen *ebiten.Image)
+}
+
+// Draws the renderers in the order of their layers, renderers on the same
+// layer are drawn in the order they were added.
+type renderers []renderer
+
+func (self *renderers) add(layer int, draw func(screen *ebiten.Image)) {
+	*self = append(*self, renderer{layer: layer, draw: draw})
+	slices.SortStableFunc(*self, func(a, b renderer) int {
+		return a.layer - b.layer
+	})
+}
+
+// Draws the renderers on the layers from `from` up to but excluding `to`.
+func (self renderers) draw(screen *ebiten.Image, from int, to int) {
+	for _, renderer := range self {
+		if renderer.layer >= from && renderer.layer < to {
+			renderer.draw(screen)
+		}
+	}
+}
+
+func (self *ArenaScene) addRenderers() {
+	self.renderers.add(layerBackground, self.drawBackground)
+	self.renderers.add(layerTerrain, self.drawTerrain)
+	self.renderers.add(layerShips, self.drawShips)
+	self.renderers.add(layerEffects, self.drawParticles)
+	self.renderers.add(layerEffects, self.drawEffects)
+
+	self.renderers.add(layerLabels, self.drawShipLabels)
+	self.renderers.add(layerLabels, self.drawReticle)
+	self.renderers.add(layerLabels, self.drawVelocityArrow)
+
+	self.renderers.add(layerHud, self.drawBoundaryWarning)
+	self.renderers.add(layerHud, self.drawScoreboard)
+	self.renderers.add(layerHud, self.drawMatchTimer)
+	self.renderers.add(layerHud, self.drawNextRoundTimer)
+	self.renderers.add(layerHud, self.drawEliminationStatus)
+	self.renderers.add(layerHud, self.drawMinimap)
+	self.renderers.add(layerHud, self.drawBoostCooldown)
+	self.renderers.add(layerHud, self.chat.Draw)
+	self.renderers.add(layerHud, self.killFeed.Draw)
+
+	self.renderers.add(layerOverlay, self.drawOverlays)
+	self.renderers.add(layerOverlay, self.drawPausedOverlay)
+	self.renderers.add(layerOverlay, self.drawQuitPrompt)
+	self.renderers.add(layerOverlay, self.drawCrosshair)
+}
+
+// Draws the screens covering the arena depending on what we are doing.
+func (self *ArenaScene) drawOverlays(screen *ebiten.Image) {
+	// Hide the world from dead players unless they are watching someone.
+	if !self.isAlive && !self.isSpectator && self.spectatedId == types.InvalidPlayerId {
+		self.deathScene.Draw(screen)
+	}
+
+	if self.isSpectating() {
+		self.drawSpectatorHud(screen)
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyL) {
+		self.showLeaderboard(screen)
+	}
+
+	if self.showDebug {
+		self.drawDebugOverlay(screen)
+	}
+}
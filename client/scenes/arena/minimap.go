@@ -0,0 +1,49 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	minimapSize   = 160
+	minimapMargin = 10
+	minimapDot    = 4
+)
+
+// Draws every living player as a dot on a scaled down map in the bottom
+// right corner of the screen.
+func (self *ArenaScene) drawMinimap(screen *ebiten.Image) {
+	x0 := float32(self.config.ScreenWidth - minimapSize - minimapMargin)
+	y0 := float32(self.config.ScreenHeight - minimapSize - minimapMargin)
+
+	vector.DrawFilledRect(screen, x0, y0, minimapSize, minimapSize, color.RGBA{0, 0, 0, 150}, false)
+	vector.StrokeRect(screen, x0, y0, minimapSize, minimapSize, 1, color.RGBA{255, 255, 255, 120}, false)
+
+	scaleX := float32(minimapSize / self.simulation.MapWidth)
+	scaleY := float32(minimapSize / self.simulation.MapHeight)
+
+	query := donburi.NewQuery(filter.Contains(component.Player, component.Position))
+	for player := range query.Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		if !playerData.IsAlive || !playerData.IsConnected {
+			continue
+		}
+
+		position := component.Position.Get(player)
+		x := x0 + float32(position.X)*scaleX - minimapDot/2
+		y := y0 + float32(position.Y)*scaleY - minimapDot/2
+
+		dotColor := color.RGBA{255, 60, 60, 255} // Red for the enemies
+		if playerData.Id == self.playerId {
+			dotColor = color.RGBA{0, 255, 0, 255} // Green for us
+		}
+
+		vector.DrawFilledRect(screen, x, y, minimapDot, minimapDot, dotColor, false)
+	}
+}
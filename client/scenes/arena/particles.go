@@ -0,0 +1,103 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	// Upper bound on the particles alive at once, ships stop emitting when
+	// it is reached.
+	maxParticles = 300
+
+	particleLifetime = 30
+	particleSize     = 4
+
+	// Boosting ships spray more particles, faster and for longer.
+	boostParticles = 4
+)
+
+// Emits particles behind every thrusting ship, then moves and ages the
+// existing ones.
+func (self *ArenaScene) updateParticles() {
+	world := self.simulation.ECS.World
+	query := donburi.NewQuery(component.Active(component.Particle))
+
+	expired := []*donburi.Entry{}
+	for entity := range query.Iter(world) {
+		particle := component.Particle.Get(entity)
+		particle.X += particle.VelocityX
+		particle.Y += particle.VelocityY
+		particle.Age++
+
+		if particle.Age >= particle.Lifetime {
+			expired = append(expired, entity)
+		}
+	}
+	for _, entity := range expired {
+		self.particles.Put(entity)
+	}
+
+	count := query.Count(world)
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(world) {
+		playerData := component.Player.Get(player)
+		isBoosting := playerData.IsBoosting()
+		if !playerData.IsAlive || !playerData.IsConnected || (!playerData.IsMovingForward && !isBoosting) {
+			continue
+		}
+
+		position := component.Position.GetValue(player)
+		if player.HasComponent(component.Interpolation) {
+			position = component.Interpolation.Get(player).Rendered(position)
+		}
+
+		emitted, spread, speed, lifetime := 1, 0.6, 1.0, particleLifetime
+		if isBoosting {
+			emitted, spread, speed, lifetime = boostParticles, 1.0, 3.0, 2*particleLifetime
+		}
+
+		for range emitted {
+			if count >= maxParticles {
+				break
+			}
+
+			// Spray the particles out of the rear of the ship, particles move
+			// by frame while ships move by second.
+			angle := position.Angle + math.Pi + (rand.Float64()-0.5)*spread
+			speed := speed * (1 + rand.Float64())
+			tps := float64(ebiten.TPS())
+
+			component.Particle.SetValue(self.particles.Get(), component.ParticleData{
+				X:         position.X - 20*math.Sin(position.Angle),
+				Y:         position.Y + 20*math.Cos(position.Angle),
+				VelocityX: position.VelocityX*0.5/tps + speed*math.Sin(angle),
+				VelocityY: position.VelocityY*0.5/tps - speed*math.Cos(angle),
+				Lifetime:  lifetime,
+			})
+			count++
+		}
+	}
+}
+
+// Draws the particles shrinking and fading out as they age.
+func (self *ArenaScene) drawParticles(screen *ebiten.Image) {
+	for entity := range donburi.NewQuery(component.Active(component.Particle)).Iter(self.simulation.ECS.World) {
+		particle := component.Particle.Get(entity)
+		remaining := 1 - particle.Progress()
+
+		// Match the center of the ship sprites.
+		x := float32(particle.X + self.camera.X + 4)
+		y := float32(particle.Y + self.camera.Y + 4)
+
+		alpha := uint8(200 * remaining)
+		tint := color.RGBA{alpha, uint8(float64(alpha) * 0.6), 0, alpha}
+		vector.DrawFilledCircle(screen, x, y, float32(particleSize*remaining), tint, false)
+	}
+}
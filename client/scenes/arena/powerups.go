@@ -0,0 +1,74 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/server/messages"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Power-ups are fully owned by the server so we only mirror what it says.
+func (self *ArenaScene) applyPowerUpSnapshot(powerUps []messages.PowerUpData) {
+	inSnapshot := make(map[types.PowerUpId]bool)
+
+	for _, data := range powerUps {
+		inSnapshot[data.PowerUpId] = true
+
+		if self.simulation.FindCorrespondingPowerUp(data.PowerUpId) == nil {
+			self.simulation.CreatePowerUp(data.PowerUpId, &data.Position, data.Kind)
+		}
+	}
+
+	for powerUp := range donburi.NewQuery(filter.Contains(component.PowerUp)).Iter(self.simulation.ECS.World) {
+		if !inSnapshot[component.PowerUp.Get(powerUp).Id] {
+			self.simulation.QueueRemoval(powerUp)
+		}
+	}
+}
+
+// Draws a shield around the ship, fading as it wears down, and the icons of
+// the active power-ups above its name.
+func (self *ArenaScene) drawPowerUpEffects(screen *ebiten.Image, position *component.PositionData, player *component.PlayerData) {
+	// The center of the ship on the screen.
+	x := position.X + self.camera.X + 4
+	y := position.Y + self.camera.Y + 4
+
+	if player.Shield > 0 {
+		shieldSize := float64(assets.Shield.Bounds().Dx()) * 4
+
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(4, 4)
+		opts.GeoM.Translate(x-shieldSize/2, y-shieldSize/2)
+		opts.ColorScale.ScaleAlpha(float32(0.6 * player.Shield / game.MaxShield))
+		screen.DrawImage(assets.Shield, opts)
+	}
+
+	icons := []*ebiten.Image{}
+	if player.HasRapidFire() {
+		icons = append(icons, assets.RapidFirePowerUp)
+	}
+	if player.HasSpeedBoost() {
+		icons = append(icons, assets.SpeedBoostPowerUp)
+	}
+	if player.HasShotgun() {
+		icons = append(icons, assets.ShotgunPowerUp)
+	}
+	if player.HasMissile() {
+		icons = append(icons, assets.MissilePowerUp)
+	}
+
+	const iconSize = 16.0
+	x -= float64(len(icons)) * iconSize / 2
+
+	for i, icon := range icons {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(2, 2)
+		opts.GeoM.Translate(x+float64(i)*iconSize, y-85)
+		screen.DrawImage(icon, opts)
+	}
+}
@@ -0,0 +1,64 @@
+package arena
+
+import "astro-blasters/game/component"
+
+// The most moves kept around waiting for a correction from the server.
+const maxPendingMoves = 128
+
+type pendingMove struct {
+	sequence uint32
+	position component.PositionData
+}
+
+// Remembers where we predicted our ship to be whenever a move was sent, so
+// that a late correction from the server can be applied on top of everything
+// predicted since.
+type prediction struct {
+	sequence uint32
+	pending  []pendingMove
+}
+
+// Records the position at which a move is sent and returns its sequence.
+func (self *prediction) record(position component.PositionData) uint32 {
+	self.sequence++
+	self.pending = append(self.pending, pendingMove{sequence: self.sequence, position: position})
+
+	if len(self.pending) > maxPendingMoves {
+		self.pending = self.pending[len(self.pending)-maxPendingMoves:]
+	}
+	return self.sequence
+}
+
+// Moves the current position by how far off the prediction was at the given
+// sequence, dropping the moves the server has already processed.
+func (self *prediction) reconcile(sequence uint32, authoritative component.PositionData, current *component.PositionData) {
+	for i, move := range self.pending {
+		if move.sequence != sequence {
+			continue
+		}
+
+		current.X += authoritative.X - move.position.X
+		current.Y += authoritative.Y - move.position.Y
+		current.Angle = component.WrapAngle(current.Angle + component.AngleDifference(authoritative.Angle, move.position.Angle))
+		current.VelocityX += authoritative.VelocityX - move.position.VelocityX
+		current.VelocityY += authoritative.VelocityY - move.position.VelocityY
+
+		self.pending = self.pending[i+1:]
+		return
+	}
+
+	// We no longer know what we predicted back then.
+	*current = authoritative
+	self.pending = nil
+}
+
+// Drops the moves the server has processed without correcting us.
+func (self *prediction) acknowledge(sequence uint32) {
+	for len(self.pending) > 0 && self.pending[0].sequence <= sequence {
+		self.pending = self.pending[1:]
+	}
+}
+
+func (self *prediction) hasPendingMoves() bool {
+	return len(self.pending) > 0
+}
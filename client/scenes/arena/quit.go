@@ -0,0 +1,78 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/scenes"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Opens the quit prompt on escape and answers it, returns whether we left the
+// arena.
+func (self *ArenaScene) handleQuitPrompt(controller *scenes.AppController, wasTyping bool) bool {
+	// Escape first closes the chat.
+	if !wasTyping && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		self.isQuitting = !self.isQuitting
+		return false
+	}
+
+	if !self.isQuitting {
+		return false
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyY) {
+		// Leaving disposes the arena, which closes the connection.
+		controller.ReturnToMenu()
+		return true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
+		self.isQuitting = false
+	}
+	return false
+}
+
+func (self *ArenaScene) drawPausedOverlay(screen *ebiten.Image) {
+	if !self.isPaused {
+		return
+	}
+
+	width := float64(self.config.ScreenWidth)
+	height := float64(self.config.ScreenHeight)
+	vector.DrawFilledRect(screen, 0, 0, float32(width), float32(height), color.RGBA{0, 0, 0, 150}, false)
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 48}
+	message := "Paused"
+	textWidth, textHeight := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(width/2-textWidth/2, height/2-textHeight/2)
+	text.Draw(screen, message, font, opts)
+}
+
+func (self *ArenaScene) drawQuitPrompt(screen *ebiten.Image) {
+	if !self.isQuitting {
+		return
+	}
+
+	width := float32(self.config.ScreenWidth)
+	height := float32(self.config.ScreenHeight)
+	vector.DrawFilledRect(screen, 0, 0, width, height, color.RGBA{0, 0, 0, 150}, false)
+
+	const boxWidth, boxHeight = 360, 110
+	x := (width - boxWidth) / 2
+	y := (height - boxHeight) / 2
+	vector.DrawFilledRect(screen, x, y, boxWidth, boxHeight, color.RGBA{20, 20, 40, 230}, false)
+	vector.StrokeRect(screen, x, y, boxWidth, boxHeight, 2, color.White, false)
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 32}
+	message := "Quit to menu? Y/N"
+	textWidth, textHeight := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(width)/2-textWidth/2, float64(height)/2-textHeight/2)
+	text.Draw(screen, message, font, opts)
+}
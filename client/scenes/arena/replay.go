@@ -0,0 +1,61 @@
+package arena
+
+import (
+	"astro-blasters/client/config"
+	"astro-blasters/client/replay"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"time"
+)
+
+// How long the end of a replay stays on screen before going back to the menu.
+const replayEndDelay = 2 * time.Second
+
+// Creates an arena playing back a recorded match instead of talking to a
+// server. The match is watched as a spectator, starting from the ship of
+// whoever recorded it.
+func NewReplayScene(config *config.ClientConfig, recording *replay.Replay) *ArenaScene {
+	// The recording player's ship is just another ship to watch.
+	response := recording.Response
+	recordedBy := response.PlayerId
+	response.PlayerId = types.InvalidPlayerId
+
+	scene := NewArenaScene(config, "", true, nil, response)
+	scene.replay = recording
+	scene.spectatedId = recordedBy
+	return scene
+}
+
+// Queues the recorded messages at the pace they were received, then has
+// `Update` go back to the menu.
+func (self *ArenaScene) playReplay() {
+	started := time.Now()
+
+	for _, entry := range self.replay.Entries {
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-time.After(time.Until(started.Add(entry.Elapsed))):
+		}
+
+		// The results would offer to join the next match, rounds played in
+		// the same arena carry on.
+		if entry.Message.MessageType == "MatchEnded" {
+			var event messages.MatchEnded
+			if err := rpc.DecodeExpectedMessage(entry.Message, &event); err != nil || event.NextRoundIn == 0 {
+				break
+			}
+		}
+
+		if !self.queueMessage(entry.Message) {
+			return
+		}
+	}
+
+	select {
+	case <-self.ctx.Done():
+	case <-time.After(replayEndDelay):
+		close(self.messages)
+	}
+}
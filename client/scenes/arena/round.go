@@ -0,0 +1,77 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/results"
+	"astro-blasters/game/component"
+	"astro-blasters/server/messages"
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Leaves for the final scoreboard once the server ended the match for good,
+// reporting whether we did.
+func (self *ArenaScene) showResults(controller *scenes.AppController) bool {
+	select {
+	case players := <-self.matchResults:
+		controller.ChangeScene(results.NewResultsScene(self.config, self.handshake(), players))
+		return true
+	default:
+		return false
+	}
+}
+
+// Clears the map and puts every ship where the server says the new round
+// starts, without leaving the arena.
+func (self *ArenaScene) resetRound(players []messages.PlayerData) {
+	self.simulation.ClearWorld()
+	self.nextRoundWhen = time.Time{}
+
+	// Moves predicted during the last round don't apply to the new positions.
+	self.prediction.pending = nil
+
+	for _, data := range players {
+		player := self.simulation.FindCorrespondingPlayer(data.PlayerId)
+		if player == nil {
+			player = self.createRemotePlayer(data.PlayerId, &data.Position, data.PlayerName, data.IsConnected, data.Team, data.Class)
+		}
+
+		self.simulation.RespawnPlayer(player, data.Position)
+		applyPlayerData(player, data)
+		playerData := component.Player.Get(player)
+		playerData.IsAlive = data.IsAlive
+		playerData.ClearPowerUps()
+
+		// Remote ships jump to their spawn point instead of gliding there.
+		if player.HasComponent(component.Interpolation) {
+			component.Interpolation.SetValue(player, component.InterpolationData{LastRetargeted: time.Now()})
+		}
+	}
+
+	if self.player != nil {
+		self.isAlive = component.Player.Get(self.player).IsAlive
+		self.deathScene = NewDeathScene(self.config, time.Time{})
+		self.camera.FocusTarget(component.Position.GetValue(self.player))
+	}
+}
+
+// Draws how long until the next round where the match timer was.
+func (self *ArenaScene) drawNextRoundTimer(screen *ebiten.Image) {
+	if self.nextRoundWhen.IsZero() {
+		return
+	}
+
+	remaining := max(time.Until(self.nextRoundWhen), 0).Round(time.Second)
+	message := fmt.Sprintf("Next round in %d", int(remaining.Seconds()))
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 48}
+	width, _ := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(self.config.ScreenWidth)/2-width/2, 8)
+	text.Draw(screen, message, font, opts)
+}
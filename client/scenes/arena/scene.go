@@ -3,15 +3,20 @@ package arena
 import (
 	"astro-blasters/assets"
 	"astro-blasters/client/config"
+	"astro-blasters/client/network"
+	"astro-blasters/client/replay"
 	"astro-blasters/client/scenes"
 	"astro-blasters/client/scenes/common"
+	"astro-blasters/client/scenes/common/failure"
 	"astro-blasters/game"
 	"astro-blasters/game/component"
 	"astro-blasters/game/types"
 	"astro-blasters/rpc"
 	"astro-blasters/server/messages"
 	"context"
+	"errors"
 	"fmt"
+	"image"
 	"image/color"
 	"math"
 	"math/rand/v2"
@@ -28,162 +33,744 @@ import (
 	"github.com/yohamta/donburi/filter"
 )
 
+// How far, in pixels, our own ship may drift from the server's position
+// before it gets corrected by a world snapshot.
+const snapshotTolerance = 3.0
+
+// How long remote ships keep moving on their own without hearing from the
+// server, after that they hold still until the next update.
+const maxExtrapolation = 250 * time.Millisecond
+
+// How far, in radians, the cursor has to move before the server is told
+// about the new aim.
+const aimTolerance = 0.035
+
+// How far, in radians, the heading of the analog stick has to move before the
+// server is told about the new one.
+const steerTolerance = 0.035
+
+// How long a ship flashes red after being hit.
+const hitFlashDuration = 150 * time.Millisecond
+
+const (
+	// How long protected ships stay protected after the last snapshot that
+	// said they were.
+	invulnerabilityGrace = 200 * time.Millisecond
+
+	// How many milliseconds protected ships spend visible, then faded.
+	invulnerabilityBlinkInterval = 100
+)
+
+// The most messages waiting for `Update`, the receiver stops reading from the
+// connection while the queue is full.
+const maxQueuedMessages = 256
+
+const (
+	// How long a write may take before it is considered failed.
+	writeTimeout = time.Second
+
+	// The consecutive failed writes after which we reconnect.
+	maxWriteFailures = 3
+)
+
 type ArenaScene struct {
 	background1 *common.Background
 	background2 *common.Background
 	config      *config.ClientConfig
 
-	simulation     *game.GameSimulation
-	shakeDuration  int
-	shakeIntensity float64
-	camera         *Camera
+	// The arena is drawn here before being scaled to the screen.
+	world *ebiten.Image
+
+	simulation *game.GameSimulation
+	shake      screenShake
+	camera     *Camera
 
 	lastFireTime time.Time
 
-	connection *websocket.Conn
-	player     *donburi.Entry
-	playerName string
-	playerId   types.PlayerId
+	// Cancelled once the player leaves the arena.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	connection    *websocket.Conn
+	writeFailures int
+	networkStats  networkStats
+	lastPing      time.Time
+	lastPong      time.Time
+	lastUpdate    time.Time
+	showDebug     bool
+	initialState  messages.ConnectionHandshakeResponse
+	player        *donburi.Entry
+	playerName    string
+	playerId      types.PlayerId
+
+	// Thrusting ships emit particles every frame, they are reused rather
+	// than created anew.
+	particles *component.Pool
+
+	// Set when playing back a recorded match, there is no connection then.
+	replay *replay.Replay
+
+	// Writes down every message received when recording the match.
+	recorder *replay.Recorder
+
+	// What is drawn, layer by layer.
+	renderers renderers
+
+	// The server sends the players that changed since the last snapshot we
+	// acknowledged.
+	snapshots snapshotHistory
 
 	deathScene *DeathScene
+	input      inputState
+	prediction prediction
+
+	isSpectator bool
+	spectatedId types.PlayerId
+
+	// Where spectators flying the camera around are looking, used instead of
+	// following a ship while `isFreeCamera` is set.
+	isFreeCamera bool
+	freeCamera   component.PositionData
+
+	chat     Chat
+	killFeed KillFeed
 
 	isAlive bool
 
+	// The lives every player starts with, 0 outside of elimination games.
+	lives int
+
+	// Lets us take our ship back when we reconnect after losing the
+	// connection.
+	reconnectToken string
+
+	// Messages from the server or the replay, read on another goroutine and
+	// handled by `Update` which owns the world. Closed once a replay is over.
+	messages   chan rpc.BaseMessage
+	dispatcher *rpc.Dispatcher
+
+	// New connections made after losing the old one, handed from the
+	// receiving goroutine to `Update` which rebuilds the world out of them.
+	reconnections chan reconnection
+
+	// Why reconnecting failed, handed to `Update` which leaves the arena to
+	// show it.
+	failures chan error
+
+	// Whether the prompt asking to quit to the menu is open.
+	isQuitting bool
+
+	// Whether we replaced the system cursor with a crosshair.
+	isCursorHidden bool
+
+	// The server pauses practice games against bots while the window is out
+	// of focus, online games carry on regardless.
+	isFocused bool
+	isPaused  bool
+
+	// When the match ends according to the server, zero for endless matches.
+	matchEndsWhen time.Time
+
+	// When the next round starts, zero unless the match just ended and the
+	// server keeps us in the arena for another round.
+	nextRoundWhen time.Time
+
+	// The final scores once the match is over, the arena is left for them
+	// once the messages of the frame are handled.
+	matchResults chan []messages.PlayerData
+
 	scrollOffset int
 }
 
-func NewArenaScene(config *config.ClientConfig, playerName string) *ArenaScene {
-	return &ArenaScene{
-		background1: common.NewBackground(game.MapWidth, game.MapHeight),
-		background2: common.NewBackground(config.ScreenWidth, config.ScreenHeight),
-		playerName:  playerName,
-		camera:      NewCamera(0, 0, game.MapHeight, game.MapWidth, config),
-		deathScene:  NewDeathScene(config),
-		isAlive:     true,
-		config:      config,
+// Creates the arena out of an established connection to the server, where
+// the response holds the state of the world when we joined.
+func NewArenaScene(config *config.ClientConfig, playerName string, isSpectator bool, connection *websocket.Conn, response messages.ConnectionHandshakeResponse) *ArenaScene {
+	ctx, cancel := context.WithCancel(context.Background())
+	mapWidth, mapHeight := mapSize(response)
+	scene := &ArenaScene{
+		ctx:          ctx,
+		cancel:       cancel,
+		isSpectator:  isSpectator,
+		spectatedId:  types.InvalidPlayerId,
+		background1:  common.NewBackground(int(mapWidth), int(mapHeight)),
+		background2:  newViewBackground(config),
+		world:        newWorldView(config),
+		playerName:   playerName,
+		camera:       NewCamera(0, 0, mapWidth, mapHeight, config),
+		deathScene:   NewDeathScene(config, time.Time{}),
+		isAlive:      true,
+		isFocused:    true,
+		config:       config,
+		connection:   connection,
+		initialState: response,
 	}
+	scene.addRenderers()
+	return scene
 }
 
 func (self *ArenaScene) Configure(controller *scenes.AppController) error {
 	controller.ChangeMusic(assets.BattleMusic)
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-	connection, _, err := websocket.Dial(ctx, self.config.ServerWebsocketURL, nil)
+	self.initializeWorld(controller, self.connection, self.initialState)
 
-	if err != nil {
-		return fmt.Errorf("Failed to connect to the server at %s", self.config.ServerWebsocketURL)
+	if self.replay == nil && self.config.RecordPath != "" {
+		recorder, err := replay.NewRecorder(self.config.RecordPath, self.initialState)
+		if err != nil {
+			return err
+		}
+		self.recorder = recorder
 	}
 
-	connectionHandshake := rpc.NewBaseMessage(messages.ConnectionHandshake{PlayerName: self.playerName})
-	if err := rpc.WriteMessage(ctx, connection, connectionHandshake); err != nil {
-		return fmt.Errorf("Failed to send handshake to the server at %s", self.config.ServerWebsocketURL)
+	self.messages = make(chan rpc.BaseMessage, maxQueuedMessages)
+	self.dispatcher = self.newDispatcher(controller)
+	self.reconnections = make(chan reconnection)
+	self.failures = make(chan error, 1)
+	self.matchResults = make(chan []messages.PlayerData, 1)
+	go self.receiveServerUpdates()
+	return nil
+}
+
+// Stops receiving updates from the server and closes the connection.
+func (self *ArenaScene) Dispose() {
+	self.cancel()
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+
+	if self.recorder != nil {
+		if err := self.recorder.Close(); err != nil {
+			self.config.Logger.Error("Failed to save the replay", "path", self.config.RecordPath, "err", err)
+		}
+	}
+	if self.replay != nil {
+		return
 	}
 
-	var response messages.ConnectionHandshakeResponse
-	if err := rpc.ReceiveExpectedMessage(ctx, connection, &response); err != nil {
-		return fmt.Errorf("Error receiving handshake response: " + err.Error())
+	// Closing waits for the server to acknowledge, which shouldn't hold up
+	// the next scene.
+	go self.connection.Close(websocket.StatusNormalClosure, "left the arena")
+}
+
+func (self *ArenaScene) handshake() messages.ConnectionHandshake {
+	handshake := messages.ConnectionHandshake{PlayerName: self.playerName, IsSpectator: self.isSpectator, ReconnectToken: self.reconnectToken}
+	if self.player != nil {
+		handshake.ShipClass = component.Player.Get(self.player).Class
 	}
+	return handshake
+}
 
-	self.connection = connection
-	self.simulation = game.NewGameSimulation()
+// The size of the map the server plays on, replays recorded before servers
+// sent it were played on the default one.
+func mapSize(response messages.ConnectionHandshakeResponse) (float64, float64) {
+	if response.MapWidth <= 0 || response.MapHeight <= 0 {
+		return game.DefaultMapWidth, game.DefaultMapHeight
+	}
+	return response.MapWidth, response.MapHeight
+}
+
+// Builds a fresh simulation out of the state sent by the server during the
+// handshake.
+func (self *ArenaScene) initializeWorld(controller *scenes.AppController, connection *websocket.Conn, response messages.ConnectionHandshakeResponse) {
+	simulation := game.NewGameSimulation()
+	simulation.WorldMode = response.WorldMode
+	simulation.MapWidth, simulation.MapHeight = mapSize(response)
+	if len(response.Weapons) > 0 {
+		simulation.Weapons = response.Weapons
+	}
+
+	// Deltas from a new connection are never based on the old snapshots.
+	self.snapshots = snapshotHistory{}
 
-	self.simulation.OnBulletCollide = func(player, bullet *donburi.Entry) {
+	simulation.OnAsteroidCollide = func(player, asteroid *donburi.Entry) {
 		if component.Player.Get(player).Id == self.playerId {
-			self.startShake(10, 10)
+			self.startShake(20, 15)
+		} else {
+			self.startShakeAt(component.Position.Get(player), 20, 10)
 		}
-		controller.PlaySfx(assets.Hit)
+		controller.PlaySfx(assets.Explosion)
 	}
 
+	self.simulation = simulation
+	self.particles = component.NewPool(simulation.ECS.World, component.Particle)
+	self.connection = connection
+	self.player = nil
+	self.playerId = response.PlayerId
+	self.isAlive = true
+	self.lives = response.Lives
+	self.reconnectToken = response.ReconnectToken
+	self.prediction = prediction{}
+	self.lastPong = time.Now()
+	self.lastUpdate = time.Now()
+
 	for _, player := range response.PlayerData {
 		if player.PlayerId == response.PlayerId {
 			// Focus the camera on the player.
-			self.player = self.simulation.CreatePlayer(player.PlayerId, &player.Position, player.PlayerName, player.IsConnected)
-			self.playerId = player.PlayerId
+			self.player = self.simulation.CreatePlayer(player.PlayerId, &player.Position, player.PlayerName, player.IsConnected, player.Team, player.Class)
+			component.Player.Get(self.player).Lives = player.Lives
 			self.camera.FocusTarget(player.Position)
 			continue
 		}
 
-		self.simulation.CreatePlayer(player.PlayerId, &player.Position, player.PlayerName, player.IsConnected)
+		remotePlayer := self.createRemotePlayer(player.PlayerId, &player.Position, player.PlayerName, player.IsConnected, player.Team, player.Class)
+		applyPlayerData(remotePlayer, player)
 	}
+}
 
-	go self.receiveServerUpdates(controller)
-	return nil
+// A connection made after losing the old one, `applied` is closed once the
+// world was rebuilt out of it.
+type reconnection struct {
+	connection *websocket.Conn
+	response   messages.ConnectionHandshakeResponse
+	applied    chan struct{}
 }
 
-func (self *ArenaScene) Draw(screen *ebiten.Image) {
-	screen.Clear()
+// Tries to connect back to the server after losing the connection. The world
+// is only rebuilt by `Update`, so this waits for it to pick up the new
+// connection before carrying on with the messages from the server.
+func (self *ArenaScene) reconnect() error {
+	self.connection.CloseNow()
+
+	self.config.Logger.Info("Lost the connection to the server, reconnecting")
+	connection, response, err := network.Connect(self.ctx, self.config.Endpoint(), self.handshake())
+	if err != nil {
+		self.config.Logger.Error("Failed to reconnect to the server", "err", err)
+		return err
+	}
+
+	reconnected := reconnection{connection: connection, response: response, applied: make(chan struct{})}
+	select {
+	case self.reconnections <- reconnected:
+	case <-self.ctx.Done():
+		connection.CloseNow()
+		return self.ctx.Err()
+	}
+
+	select {
+	case <-reconnected.applied:
+		return nil
+	case <-self.ctx.Done():
+		return self.ctx.Err()
+	}
+}
 
-	if self.shakeDuration > 0 {
-		self.camera.X += (rand.Float64()*2 - 1) * self.shakeIntensity
-		self.camera.Y += (rand.Float64()*2 - 1) * self.shakeIntensity
-		self.shakeDuration -= 1
+// Starts over with the state the server gave us when we reconnected, if we
+// did.
+func (self *ArenaScene) applyReconnection(controller *scenes.AppController) {
+	select {
+	case reconnected := <-self.reconnections:
+		// Whatever the old connection still had for us is about the world
+		// that is rebuilt here.
+		for len(self.messages) > 0 {
+			<-self.messages
+		}
+		self.initializeWorld(controller, reconnected.connection, reconnected.response)
+		close(reconnected.applied)
+	default:
 	}
+}
 
-	self.drawBackground(screen)
-	self.drawEntities(screen)
+// Handles the messages received since the last frame, reporting whether the
+// replay we were watching is over and we left the arena.
+func (self *ArenaScene) handleMessages(controller *scenes.AppController) bool {
+	for {
+		select {
+		case message, ok := <-self.messages:
+			if !ok {
+				controller.ReturnToMenu()
+				return true
+			}
 
-	if !self.isAlive {
-		self.deathScene.Draw(screen)
+			// Messages that fail to decode are dropped.
+			if err := self.dispatcher.Dispatch(message); err != nil {
+				self.networkStats.recordDecodeError(err.(*rpc.DecodeError), self.config.Logger)
+			}
+		default:
+			return false
+		}
 	}
+}
 
-	if ebiten.IsKeyPressed(ebiten.KeyL) {
-		self.showLeaderboard(screen)
+// Leaves for the failure scene once we gave up on reconnecting, reporting
+// whether we did.
+func (self *ArenaScene) showFailure(controller *scenes.AppController) bool {
+	select {
+	case err := <-self.failures:
+		controller.ChangeScene(failure.NewFailureScene(self.config, err))
+		return true
+	default:
+		return false
 	}
 }
 
+func (self *ArenaScene) Draw(screen *ebiten.Image) {
+	screen.Clear()
+
+	// The shake only moves what is drawn this frame.
+	shakeX, shakeY := self.shake.offset()
+	self.camera.X += shakeX
+	self.camera.Y += shakeY
+	defer func() {
+		self.camera.X -= shakeX
+		self.camera.Y -= shakeY
+	}()
+
+	// The arena is drawn at its native size and then scaled to the screen,
+	// the HUD on top of it is drawn on the screen directly.
+	width, height := self.camera.ViewSize()
+	view := self.world.SubImage(image.Rect(0, 0, int(math.Ceil(width)), int(math.Ceil(height)))).(*ebiten.Image)
+	view.Clear()
+	self.renderers.draw(view, layerBackground, layerHud)
+
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(self.camera.Scale(), self.camera.Scale())
+	opts.Filter = ebiten.FilterNearest
+	screen.DrawImage(view, opts)
+
+	self.renderers.draw(screen, layerHud, layerOverlay+1)
+}
+
+// Returns an image holding the part of the arena that fits on the screen,
+// large enough for the camera zoomed all the way out.
+func newWorldView(config *config.ClientConfig) *ebiten.Image {
+	width, height := config.ViewSize()
+	return ebiten.NewImage(int(math.Ceil(width/MinCameraZoom)), int(math.Ceil(height/MinCameraZoom)))
+}
+
+func newViewBackground(config *config.ClientConfig) *common.Background {
+	width, height := config.ViewSize()
+	return common.NewBackground(int(math.Ceil(width/MinCameraZoom)), int(math.Ceil(height/MinCameraZoom)))
+}
+
 func (self *ArenaScene) Update(controller *scenes.AppController) {
-	if self.isAlive {
+	if self.handleMessages(controller) {
+		return
+	}
+	self.applyReconnection(controller)
+	if self.showFailure(controller) {
+		return
+	}
+	if self.showResults(controller) {
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
+		self.showDebug = !self.showDebug
+	}
+	self.pingServer()
+	self.networkStats.sample()
+
+	if isFocused := ebiten.IsFocused(); isFocused != self.isFocused {
+		self.isFocused = isFocused
+		self.sendMessage(rpc.NewBaseMessage(messages.UpdateFocus{IsFocused: isFocused}))
+	}
+	self.updateCursor()
+
+	// The thruster goes quiet while paused too.
+	isThrusting := self.player != nil && self.isAlive && component.Player.Get(self.player).IsMovingForward
+	controller.PlayThruster(isThrusting && !self.isPaused)
+
+	if self.isPaused {
+		// Time spent paused shouldn't be simulated once we resume.
+		self.lastUpdate = time.Now()
+		return
+	}
+
+	wasTyping := self.chat.isTyping
+	if !self.isQuitting {
+		if text, send := self.chat.Update(); send {
+			self.sendMessage(rpc.NewBaseMessage(messages.ChatMessage{Text: text}))
+		}
+	}
+
+	if self.handleQuitPrompt(controller, wasTyping) {
+		return
+	}
+
+	if self.isSpectating() {
+		// The server stops every move on death, so held keys have to be
+		// sent again after respawning.
+		self.input = inputState{}
+		if !self.isQuitting {
+			self.handleSpectatorInput()
+		}
+	} else {
+		self.spectatedId = types.InvalidPlayerId
+		self.isFreeCamera = false
+		self.camera.SetZoom(1)
 		self.handleInput()
 	}
 
-	self.simulation.Update()
+	// Hold the ships we lost track of in place rather than letting them
+	// drift off, the interpolation blends them back once we hear from the
+	// server again.
+	stale := self.staleRemotePlayers()
+	held := make([]component.PositionData, len(stale))
+	for i, player := range stale {
+		held[i] = component.Position.GetValue(player)
+	}
+
+	now := time.Now()
+	self.simulation.Update(now.Sub(self.lastUpdate).Seconds())
+	self.lastUpdate = now
 
-	position := component.Position.Get(self.player)
-	self.camera.FocusTarget(*position)
+	for i, player := range stale {
+		component.Position.SetValue(player, held[i])
+	}
+	self.updateParticles()
+
+	for entity := range donburi.NewQuery(filter.Contains(component.Interpolation)).Iter(self.simulation.ECS.World) {
+		component.Interpolation.Get(entity).Update(self.config.InterpolationFactor)
+	}
+
+	if target, ok := self.cameraTarget(); ok {
+		self.camera.FocusTarget(target)
+	}
 	self.camera.Constrain()
 }
 
 func (self *ArenaScene) handleInput() {
-	ctx := context.Background()
 	position := component.Position.Get(self.player)
 	sendMove := func(move types.PlayerMove) {
-		message := rpc.NewBaseMessage(messages.RegisterPlayerMove{Move: move, Position: *position})
-		rpc.WriteMessage(ctx, self.connection, message)
+		sequence := self.prediction.record(*position)
+		message := rpc.NewBaseMessage(messages.RegisterPlayerMove{Move: move, Position: *position, Sequence: sequence})
+		if err := self.sendMessage(message); err != nil {
+			return
+		}
+
+		// Don't wait for the server to move our own ship.
+		self.simulation.RegisterPlayerMove(self.playerId, move)
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyW) || inpututil.IsKeyJustPressed(ebiten.KeyUp) {
-		sendMove(types.PlayerStartForward)
+	previous := self.input
+	self.input = readKeyboard(self.config.KeyBindings).merge(readGamepads())
+
+	// Let go of every action while typing in the chat or deciding whether to
+	// quit.
+	if self.chat.isTyping || self.isQuitting {
+		self.input = inputState{}
 	}
-	if inpututil.IsKeyJustReleased(ebiten.KeyW) || inpututil.IsKeyJustReleased(ebiten.KeyUp) {
-		sendMove(types.PlayerStopForward)
+
+	sendTransition := func(wasHeld, isHeld bool, start, stop types.PlayerMove) {
+		if !wasHeld && isHeld {
+			sendMove(start)
+		} else if wasHeld && !isHeld {
+			sendMove(stop)
+		}
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyD) || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
-		sendMove(types.PlayerStartRotateClockwise)
+	sendTransition(previous.isMovingForward, self.input.isMovingForward, types.PlayerStartForward, types.PlayerStopForward)
+	sendTransition(previous.isRotatingClockwise, self.input.isRotatingClockwise, types.PlayerStartRotateClockwise, types.PlayerStopRotateClockwise)
+	sendTransition(previous.isRotatingCounterClockwise, self.input.isRotatingCounterClockwise, types.PlayerStartRotateCounterClockwise, types.PlayerStopRotateCounterClockwise)
+	sendTransition(previous.isFiring, self.input.isFiring, types.PlayerStartFireBullet, types.PlayerStopFireBullet)
+
+	// Boosting is a single kick, holding the key doesn't boost again.
+	if !previous.isBoosting && self.input.isBoosting && game.BoostCooldownRemaining(component.Player.Get(self.player)) == 0 {
+		sendMove(types.PlayerBoost)
 	}
-	if inpututil.IsKeyJustReleased(ebiten.KeyD) || inpututil.IsKeyJustReleased(ebiten.KeyRight) {
-		sendMove(types.PlayerStopRotateClockwise)
+
+	self.steer()
+
+	if self.config.AimAtCursor {
+		self.aimAtCursor(position)
 	}
+}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
-		sendMove(types.PlayerStartRotateCounterClockwise)
+// Turns the ship towards the heading of the analog stick, telling the server
+// only when the heading moved noticeably or the stick was let go.
+func (self *ArenaScene) steer() {
+	playerData := component.Player.Get(self.player)
+	if self.input.isSteering == playerData.IsSteering {
+		if !self.input.isSteering || math.Abs(component.AngleDifference(playerData.SteerAngle, self.input.steerAngle)) < steerTolerance {
+			return
+		}
 	}
-	if inpututil.IsKeyJustReleased(ebiten.KeyA) || inpututil.IsKeyJustReleased(ebiten.KeyLeft) {
-		sendMove(types.PlayerStopRotateCounterClockwise)
+
+	message := rpc.NewBaseMessage(messages.UpdateSteering{IsSteering: self.input.isSteering, Angle: self.input.steerAngle})
+	if err := self.sendMessage(message); err != nil {
+		return
 	}
+	self.simulation.RegisterPlayerSteering(self.playerId, self.input.isSteering, self.input.steerAngle)
+}
+
+// Points the guns of the ship at the mouse cursor, telling the server only
+// when the aim moved noticeably.
+func (self *ArenaScene) aimAtCursor(position *component.PositionData) {
+	cursorX, cursorY := ebiten.CursorPosition()
+	scale := self.camera.Scale()
 
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		sendMove(types.PlayerStartFireBullet)
+	// The cursor in world coordinates, relative to the center of the ship.
+	dx := float64(cursorX)/scale - self.camera.X - position.X - 4
+	dy := float64(cursorY)/scale - self.camera.Y - position.Y - 4
+	angle := math.Atan2(dx, -dy)
+
+	playerData := component.Player.Get(self.player)
+	if playerData.IsAiming && math.Abs(component.AngleDifference(playerData.AimAngle, angle)) < aimTolerance {
+		return
+	}
+
+	message := rpc.NewBaseMessage(messages.UpdateAim{IsAiming: true, Angle: angle})
+	if err := self.sendMessage(message); err != nil {
+		return
+	}
+	playerData.IsAiming = true
+	playerData.AimAngle = angle
+}
+
+// Sends the message to the server, giving up once we leave the arena or the
+// write takes too long. Failing repeatedly drops the connection so that the
+// receiver reconnects.
+func (self *ArenaScene) sendMessage(message rpc.BaseMessage) error {
+	// Nobody is listening to a replay.
+	if self.replay != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(self.ctx, writeTimeout)
+	defer cancel()
+
+	err := rpc.WriteMessage(ctx, self.connection, message)
+	if err == nil {
+		self.writeFailures = 0
+		self.networkStats.recordMessageOut()
+		return nil
 	}
-	if inpututil.IsKeyJustReleased(ebiten.KeySpace) {
-		sendMove(types.PlayerStopFireBullet)
+
+	self.writeFailures++
+	if self.writeFailures >= maxWriteFailures {
+		self.writeFailures = 0
+		self.connection.CloseNow()
 	}
+	return err
 }
 
-func (self *ArenaScene) startShake(duration int, intensity float64) {
-	self.shakeDuration = duration
-	self.shakeIntensity = intensity
+// Creates a player controlled by another client, their position is smoothed
+// out when corrected by the server.
+func (self *ArenaScene) createRemotePlayer(playerId types.PlayerId, position *component.PositionData, playerName string, isConnected bool, team types.Team, class types.ShipClass) *donburi.Entry {
+	player := self.simulation.CreatePlayer(playerId, position, playerName, isConnected, team, class)
+	player.AddComponent(component.Interpolation)
+	component.Interpolation.Get(player).LastRetargeted = time.Now()
+	return player
+}
+
+// Returns the remote players we haven't heard of for too long to keep
+// extrapolating where they are.
+func (self *ArenaScene) staleRemotePlayers() []*donburi.Entry {
+	stale := []*donburi.Entry{}
+	for player := range donburi.NewQuery(filter.Contains(component.Player, component.Interpolation)).Iter(self.simulation.ECS.World) {
+		if time.Since(component.Interpolation.Get(player).LastRetargeted) > maxExtrapolation {
+			stale = append(stale, player)
+		}
+	}
+	return stale
+}
+
+// Moves the player to the position dictated by the server.
+func (self *ArenaScene) correctPlayerPosition(player *donburi.Entry, position component.PositionData) {
+	if player.HasComponent(component.Interpolation) {
+		interpolation := component.Interpolation.Get(player)
+		interpolation.Retarget(component.Position.Get(player), position)
+
+		// A ship crossing an edge should glide over it, not across the map.
+		if self.simulation.WorldMode == types.WorldWrap {
+			interpolation.OffsetX = game.WrapDelta(interpolation.OffsetX, self.simulation.MapWidth)
+			interpolation.OffsetY = game.WrapDelta(interpolation.OffsetY, self.simulation.MapHeight)
+		}
+		return
+	}
+	component.Position.SetValue(player, position)
+}
+
+// Reconciles the world with the snapshot sent by the server, creating the
+// players we missed and removing the ones that are gone.
+func (self *ArenaScene) applyWorldSnapshot(snapshot messages.WorldSnapshot) {
+	inSnapshot := make(map[types.PlayerId]bool)
+
+	for _, data := range snapshot.Players {
+		inSnapshot[data.PlayerId] = true
+
+		player := self.simulation.FindCorrespondingPlayer(data.PlayerId)
+		if player == nil {
+			player = self.createRemotePlayer(data.PlayerId, &data.Position, data.PlayerName, data.IsConnected, data.Team, data.Class)
+		}
+
+		if player != self.player {
+			self.correctPlayerPosition(player, data.Position)
+			applyPlayerData(player, data)
+			continue
+		}
+
+		// Only correct our own ship when it drifted noticeably, otherwise it
+		// would stutter. While the server has yet to process some of our
+		// moves the snapshot lags behind our prediction, so we leave those to
+		// the corrections tagged with a sequence.
+		self.prediction.acknowledge(data.LastSequence)
+		position := component.Position.Get(player)
+		if !self.prediction.hasPendingMoves() && !position.IntersectsWith(&data.Position, snapshotTolerance) {
+			self.correctPlayerPosition(player, data.Position)
+		}
+		applyPlayerData(player, data)
+	}
+
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		if player != self.player && !inSnapshot[component.Player.Get(player).Id] {
+			self.simulation.QueueRemoval(player)
+		}
+	}
+
+	self.applyAsteroidSnapshot(snapshot.Asteroids)
+	self.applyPowerUpSnapshot(snapshot.PowerUps)
+	self.applyMissileSnapshot(snapshot.Missiles)
+}
+
+// Asteroids are fully owned by the server so they are snapped to wherever it
+// says they are.
+func (self *ArenaScene) applyAsteroidSnapshot(asteroids []messages.AsteroidData) {
+	inSnapshot := make(map[types.AsteroidId]bool)
+
+	for _, data := range asteroids {
+		inSnapshot[data.AsteroidId] = true
+
+		asteroid := self.simulation.FindCorrespondingAsteroid(data.AsteroidId)
+		if asteroid == nil {
+			self.simulation.CreateAsteroid(data.AsteroidId, &data.Position, data.Radius)
+			continue
+		}
+		component.Position.SetValue(asteroid, data.Position)
+	}
+
+	for asteroid := range donburi.NewQuery(filter.Contains(component.Asteroid)).Iter(self.simulation.ECS.World) {
+		if !inSnapshot[component.Asteroid.Get(asteroid).Id] {
+			self.simulation.QueueRemoval(asteroid)
+		}
+	}
+}
+
+// Missiles are snapped to the server's positions since they steer. Ones the
+// server no longer knows about are left to expire or hit something.
+func (self *ArenaScene) applyMissileSnapshot(missiles []messages.MissileData) {
+	for _, data := range missiles {
+		missile := self.simulation.FindCorrespondingMissile(data.MissileId)
+		if missile == nil {
+			continue
+		}
+		component.Position.SetValue(missile, data.Position)
+		component.Bullet.Get(missile).TargetId = data.TargetId
+	}
+}
+
+// Copies the server's view of the player's stats.
+func applyPlayerData(player *donburi.Entry, data messages.PlayerData) {
+	playerData := component.Player.Get(player)
+	playerData.Health = data.Health
+	playerData.Shield = data.Shield
+	playerData.Score = data.Score
+	playerData.Lives = data.Lives
+	playerData.Weapon = data.Weapon
+
+	// The protection lasts for as long as the snapshots say so, and a little
+	// longer to bridge the gap to the next one.
+	playerData.InvulnerableUntil = time.Time{}
+	if data.IsInvulnerable {
+		playerData.InvulnerableUntil = time.Now().Add(invulnerabilityGrace)
+	}
 }
 
 // Draw the background.
@@ -199,87 +786,189 @@ func (self *ArenaScene) drawBackground(screen *ebiten.Image) {
 	screen.DrawImage(self.background1.Image, opts)
 }
 
-func (self *ArenaScene) drawEntities(screen *ebiten.Image) {
-	drawSprite := func(position *component.PositionData, scale float64, angleOffset float64, offset dmath.Vec2, sprite *ebiten.Image) {
-		// Center the texture.
-		x0 := float64(sprite.Bounds().Dx()) / 2
-		y0 := float64(sprite.Bounds().Dy()) / 2
+// Draws the sprite centered on the position.
+func (self *ArenaScene) drawSprite(screen *ebiten.Image, position *component.PositionData, scale float64, angleOffset float64, offset dmath.Vec2, sprite *ebiten.Image, tint ebiten.ColorScale) {
+	// Center the texture.
+	x0 := float64(sprite.Bounds().Dx()) / 2
+	y0 := float64(sprite.Bounds().Dy()) / 2
 
-		opts := &ebiten.DrawImageOptions{}
-		opts.GeoM.Translate(-x0, -y0)
-		opts.GeoM.Translate(offset.X, offset.Y)
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(-x0, -y0)
+	opts.GeoM.Translate(offset.X, offset.Y)
 
-		opts.GeoM.Rotate(position.Angle)
-		opts.GeoM.Rotate(angleOffset)
+	opts.GeoM.Rotate(position.Angle)
+	opts.GeoM.Rotate(angleOffset)
 
-		opts.GeoM.Scale(scale, scale)
-		opts.GeoM.Translate(position.X, position.Y)
-		opts.GeoM.Translate(self.camera.X+x0, self.camera.Y+y0)
+	opts.GeoM.Scale(scale, scale)
+	opts.GeoM.Translate(position.X, position.Y)
+	opts.GeoM.Translate(self.camera.X+x0, self.camera.Y+y0)
+	opts.ColorScale = tint
 
-		screen.DrawImage(sprite, opts)
-	}
+	screen.DrawImage(sprite, opts)
+}
+
+// Returns the ships that are in play along with where to draw them.
+func (self *ArenaScene) visibleShips() ([]*donburi.Entry, []*component.PositionData) {
+	ships := []*donburi.Entry{}
+	positions := []*component.PositionData{}
+
+	for entity := range donburi.NewQuery(filter.Contains(component.Player, component.Position)).Iter(self.simulation.ECS.World) {
+		player := component.Player.Get(entity)
+		if !player.IsAlive || !player.IsConnected {
+			continue
+		}
 
-	query := donburi.NewQuery(filter.Contains(component.Position))
-	for entity := range query.Iter(self.simulation.ECS.World) {
 		position := component.Position.Get(entity)
+		if entity.HasComponent(component.Interpolation) {
+			rendered := component.Interpolation.Get(entity).Rendered(*position)
+			position = &rendered
+		}
+		ships = append(ships, entity)
+		positions = append(positions, position)
+	}
+	return ships, positions
+}
 
-		if entity.HasComponent(component.Player) {
-			player := component.Player.Get(entity)
+// Draws the asteroids and the power-ups floating around the map.
+func (self *ArenaScene) drawTerrain(screen *ebiten.Image) {
+	for entity := range donburi.NewQuery(filter.Contains(component.Asteroid)).Iter(self.simulation.ECS.World) {
+		// Scale the rock so that it covers its collision radius.
+		sprite := component.Sprite.GetValue(entity)
+		scale := 2 * component.Asteroid.Get(entity).Radius / float64(sprite.Bounds().Dx())
+		self.drawSprite(screen, component.Position.Get(entity), scale, 0, dmath.NewVec2(0, 0), sprite, ebiten.ColorScale{})
+	}
 
-			if !player.IsAlive || !player.IsConnected {
-				continue
+	for entity := range donburi.NewQuery(filter.Contains(component.PowerUp)).Iter(self.simulation.ECS.World) {
+		self.drawSprite(screen, component.Position.Get(entity), 4.0, 0, dmath.NewVec2(0, 0), component.Sprite.GetValue(entity), ebiten.ColorScale{})
+	}
+}
+
+func (self *ArenaScene) drawShips(screen *ebiten.Image) {
+	ships, positions := self.visibleShips()
+	for i, entity := range ships {
+		player := component.Player.Get(entity)
+
+		// Ships that were just hit flash red.
+		tint := game.GetShipTint(player.Id, player.Team)
+		if time.Now().Before(player.HitFlashExpiresWhen) {
+			tint.Scale(1, 0.3, 0.3, 1)
+		}
+
+		// Protected ships blink until they can be damaged.
+		if player.IsInvulnerable() && time.Now().UnixMilli()/invulnerabilityBlinkInterval%2 == 0 {
+			tint.ScaleAlpha(0.3)
+		}
+
+		// Draw the player ship, twice while it straddles an edge.
+		for _, ghost := range self.wrappedCopies(positions[i]) {
+			self.drawSprite(screen, ghost, 4.0, 0, dmath.NewVec2(0, 0), component.Sprite.GetValue(entity), tint)
+		}
+
+		if player.IsMovingForward {
+			exhaust := component.Animation.Get(entity).Frame()
+			for _, ghost := range self.wrappedCopies(positions[i]) {
+				self.drawSprite(screen, ghost, 4.0, 0, dmath.NewVec2(0, 8), exhaust, ebiten.ColorScale{})
 			}
+		}
+	}
+}
+
+// Draws the bullets and explosions.
+func (self *ArenaScene) drawEffects(screen *ebiten.Image) {
+	for entity := range donburi.NewQuery(component.Active(component.Bullet)).Iter(self.simulation.ECS.World) {
+		self.drawSprite(screen, component.Position.Get(entity), 4.0, -math.Pi/4, dmath.NewVec2(0, 0), component.Sprite.GetValue(entity), ebiten.ColorScale{})
+	}
 
-			font := text.GoTextFace{Source: assets.Munro, Size: 20}
-			width, _ := text.Measure(player.Name, &font, 12)
+	for entity := range donburi.NewQuery(filter.Contains(component.Explosion)).Iter(self.simulation.ECS.World) {
+		sprite := component.Animation.Get(entity).Frame()
+		position := component.Position.GetValue(entity)
+		explosion := component.Explosion.Get(entity)
 
-			x := (position.X - width/2) + 6
-			y := position.Y - 55
+		for i := 0; i < explosion.Count; i++ {
+			position.X += 25 * rand.Float64()
+			position.Y += 25 * rand.Float64()
+			self.drawSprite(screen, &position, 4.0, 0, dmath.NewVec2(0, 0), sprite, ebiten.ColorScale{})
+		}
+	}
+}
 
-			x += self.camera.X
-			y += self.camera.Y
+// Draws the names, health bars and markers attached to the ships.
+func (self *ArenaScene) drawShipLabels(screen *ebiten.Image) {
+	ships, positions := self.visibleShips()
+	for i, entity := range ships {
+		player := component.Player.Get(entity)
+		position := positions[i]
 
-			// Set up the text drawing options
-			opts := &text.DrawOptions{}
-			opts.GeoM.Translate(x, y)
+		font := text.GoTextFace{Source: assets.Munro, Size: 20}
+		width, _ := text.Measure(player.Name, &font, 12)
 
-			text.Draw(screen, player.Name, &font, opts)
-			self.drawHealthBar(screen, position, player.Health, 100)
+		x := (position.X - width/2) + 6
+		y := position.Y - 55
 
-			// Draw the player ship
-			drawSprite(position, 4.0, 0, dmath.NewVec2(0, 0), component.Sprite.GetValue(entity))
+		x += self.camera.X
+		y += self.camera.Y
 
-			if player.Id != self.playerId {
-				enemyPosition := component.Position.Get(entity)
-				self.drawPointingArrow(screen, enemyPosition)
-			} else {
-				opts := &text.DrawOptions{}
-				opts.GeoM.Translate(10, 10)
-				text.Draw(screen, fmt.Sprintf("Score %d", player.Score), &text.GoTextFace{Source: assets.Munro, Size: 20}, opts)
-			}
+		// Set up the text drawing options
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(x, y)
 
-			if player.IsMovingForward {
-				exhaust := component.Animation.Get(entity).Frame()
-				drawSprite(position, 4.0, 0, dmath.NewVec2(0, 8), exhaust)
-			}
+		text.Draw(screen, player.Name, &font, opts)
+		self.drawHealthBar(screen, position, player.Health, game.GetShipStats(player.Class).MaxHealth)
+		self.drawPowerUpEffects(screen, position, player)
 
-		} else if entity.HasComponent(component.Explosion) {
-			sprite := component.Animation.Get(entity).Frame()
-			position := component.Position.GetValue(entity)
-			explosion := component.Explosion.Get(entity)
+		if player.Id == self.playerId {
+			self.drawSelfMarker(screen, position)
 
-			for i := 0; i < explosion.Count; i++ {
-				position.X += 25 * rand.Float64()
-				position.Y += 25 * rand.Float64()
-				drawSprite(&position, 4.0, 0, dmath.NewVec2(0, 0), sprite)
+			opts := &text.DrawOptions{}
+			opts.GeoM.Translate(10, 10)
+			text.Draw(screen, fmt.Sprintf("Score %d", player.Score), &text.GoTextFace{Source: assets.Munro, Size: 20}, opts)
+		} else {
+			self.drawPointingArrow(screen, position)
+		}
+	}
+}
+
+// Returns the position along with its copies past the opposite edges when a
+// ship near an edge of a wrapping map pokes out of it.
+func (self *ArenaScene) wrappedCopies(position *component.PositionData) []*component.PositionData {
+	copies := []*component.PositionData{position}
+	if self.simulation.WorldMode != types.WorldWrap {
+		return copies
+	}
+
+	offsetsX := []float64{0}
+	if position.X < game.ShipWidth {
+		offsetsX = append(offsetsX, self.simulation.MapWidth)
+	} else if position.X > self.simulation.MapWidth-game.ShipWidth {
+		offsetsX = append(offsetsX, -self.simulation.MapWidth)
+	}
+
+	offsetsY := []float64{0}
+	if position.Y < game.ShipHeight {
+		offsetsY = append(offsetsY, self.simulation.MapHeight)
+	} else if position.Y > self.simulation.MapHeight-game.ShipHeight {
+		offsetsY = append(offsetsY, -self.simulation.MapHeight)
+	}
+
+	for _, offsetX := range offsetsX {
+		for _, offsetY := range offsetsY {
+			if offsetX == 0 && offsetY == 0 {
+				continue
 			}
-		} else if entity.HasComponent(component.Bullet) {
-			drawSprite(position, 4.0, -math.Pi/4, dmath.NewVec2(0, 0), component.Sprite.GetValue(entity))
+			ghost := *position
+			ghost.X += offsetX
+			ghost.Y += offsetY
+			copies = append(copies, &ghost)
 		}
 	}
+	return copies
 }
 
 func (self *ArenaScene) drawPointingArrow(screen *ebiten.Image, enemyPosition *component.PositionData) {
+	if self.player == nil {
+		return
+	}
+
 	ourPosition := component.Position.Get(self.player)
 	arrow := assets.Arrows.GetTile(assets.TileIndex{X: 9, Y: 12})
 
@@ -345,10 +1034,22 @@ func (self *ArenaScene) drawHealthBar(screen *ebiten.Image, position *component.
 	// Draw the health bar foreground (current health)
 	currentHealthWidth := healthBarWidth * healthPercentage
 	healthBarForeground := ebiten.NewImage(int(currentHealthWidth), int(healthBarHeight))
-	healthBarForeground.Fill(color.RGBA{0, 255, 0, 255}) // Green for the current health
+	healthBarForeground.Fill(healthBarColor(healthPercentage))
 	screen.DrawImage(healthBarForeground, opts)
 }
 
+// Returns the color of the health bar given the fraction of health left.
+func healthBarColor(healthPercentage float64) color.RGBA {
+	switch {
+	case healthPercentage > 0.50:
+		return color.RGBA{0, 255, 0, 255} // Green
+	case healthPercentage > 0.25:
+		return color.RGBA{255, 255, 0, 255} // Yellow
+	default:
+		return color.RGBA{255, 0, 0, 255} // Red
+	}
+}
+
 func (self *ArenaScene) drawMessage(screen *ebiten.Image, image *ebiten.Image, scaleX, scaleY, rotate, translateX, translateY float64, colorScale [4]float32) {
 	opts := &ebiten.DrawImageOptions{}
 
@@ -365,83 +1066,65 @@ func (self *ArenaScene) drawMessage(screen *ebiten.Image, image *ebiten.Image, s
 	screen.DrawImage(image, opts)
 }
 
-// Receives information from the server and updates the game state accordingly.
-func (self *ArenaScene) receiveServerUpdates(controller *scenes.AppController) {
+// Receives information from the server and queues it for `Update`.
+func (self *ArenaScene) receiveServerUpdates() {
+	if self.replay != nil {
+		self.playReplay()
+		return
+	}
+
 	for {
 		var message rpc.BaseMessage
-		if err := rpc.ReceiveMessage(context.Background(), self.connection, &message); err != nil {
-			continue
-		}
-
-		switch message.MessageType {
-		case "UpdatePosition":
-			var updatePosition messages.UpdatePosition
-			if err := rpc.DecodeExpectedMessage(message, &updatePosition); err != nil {
-				continue
-			}
-			if player := self.simulation.FindCorrespondingPlayer(updatePosition.PlayerId); player != nil {
-				component.Position.SetValue(player, updatePosition.Position)
-			}
-		case "EventPlayerConnected":
-			var event messages.EventPlayerConnected
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
-				continue
-			}
-			self.simulation.CreatePlayer(event.PlayerId, &event.Position, event.PlayerName, true)
-		case "EventPlayerDisconnected":
-			var event messages.EventPlayerDisconnected
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
-				continue
-			}
-			player := self.simulation.FindCorrespondingPlayer(event.PlayerId)
-			self.simulation.RegisterPlayerDisconnection(player)
-		case "EventPlayerMove":
-			var event messages.EventPlayerMove
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
-				continue
-			}
-			self.simulation.RegisterPlayerMove(event.PlayerId, event.Move)
-		case "EventUpdateHealth":
-			var event messages.EventUpdateHealth
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
-				continue
+		if err := rpc.ReceiveMessage(self.ctx, self.connection, &message); err != nil {
+			// We are leaving the arena, there is nothing to reconnect to.
+			if self.ctx.Err() != nil {
+				return
 			}
-			self.simulation.UpdatePlayerHealth(event.PlayerId, event.Health)
-		case "EventPlayerDied":
-			var event messages.EventPlayerDied
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
+
+			// A garbled message doesn't mean the connection is gone.
+			var decodeErr *rpc.DecodeError
+			if errors.As(err, &decodeErr) {
+				self.networkStats.recordDecodeError(decodeErr, self.config.Logger)
 				continue
 			}
 
-			killed := self.simulation.FindCorrespondingPlayer(event.PlayerId)
-			killer := self.simulation.FindCorrespondingPlayer(event.KilledBy)
+			self.networkStats.recordReadError(err, self.config.Logger)
 
-			self.simulation.RegisterPlayerDeath(killed, killer)
-			if event.PlayerId == self.playerId {
-				self.deathScene = NewDeathScene(self.config)
-				self.isAlive = false
+			if err := self.reconnect(); err != nil {
+				// We left the arena while reconnecting.
+				if self.ctx.Err() != nil {
+					return
+				}
+				self.failures <- err
+				return
 			}
-			controller.PlaySfx(assets.Explosion)
-		case "EventPlayerFireBullet":
-			var event messages.EventPlayerFireBullet
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
-				continue
-			}
-			self.simulation.RegisterPlayerFire(self.simulation.FindCorrespondingPlayer(event.PlayerId))
-			controller.PlaySfx(assets.LaserAudio)
-		case "EventPlayerRespawned":
-			var event messages.EventPlayerRespawned
-			if err := rpc.DecodeExpectedMessage(message, &event); err != nil {
-				continue
+			continue
+		}
+
+		self.networkStats.recordMessageIn()
+		if self.recorder != nil {
+			if err := self.recorder.Record(message); err != nil {
+				self.config.Logger.Warn("Failed to record a message", "type", message.MessageType, "err", err)
 			}
+		}
 
-			self.simulation.RespawnPlayer(self.simulation.FindCorrespondingPlayer(event.PlayerId), event.Position)
-			self.isAlive = true
-		default:
+		if !self.queueMessage(message) {
+			return
 		}
 	}
 }
 
+// Hands the message to `Update`, reporting whether it was queued before we
+// left the arena.
+func (self *ArenaScene) queueMessage(message rpc.BaseMessage) bool {
+	select {
+	case self.messages <- message:
+		return true
+	case <-self.ctx.Done():
+		return false
+	}
+}
+
 type leaderboardEntry struct {
 	Name  string
 	Score int
@@ -464,6 +1147,73 @@ func (self *ArenaScene) getScores() []leaderboardEntry {
 	return entries
 }
 
+// Draws the top players below our own score, press L for the full
+// leaderboard.
+func (self *ArenaScene) drawScoreboard(screen *ebiten.Image) {
+	font := &text.GoTextFace{Source: assets.Munro, Size: 16}
+
+	for i, entry := range self.getScores() {
+		if i > 4 {
+			break
+		}
+
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(10, float64(40+i*20))
+		opts.ColorScale.ScaleAlpha(0.8)
+		text.Draw(screen, fmt.Sprintf("%d. %s %d", i+1, entry.Name, entry.Score), font, opts)
+	}
+
+	self.drawTeamScores(screen)
+}
+
+// Sums up the scores of each team, empty outside of the team mode.
+func (self *ArenaScene) getTeamScores() map[types.Team]int {
+	scores := map[types.Team]int{}
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		data := component.Player.Get(player)
+		if data.Team != types.TeamNone {
+			scores[data.Team] += data.Score
+		}
+	}
+	return scores
+}
+
+func (self *ArenaScene) drawTeamScores(screen *ebiten.Image) {
+	scores := self.getTeamScores()
+	if len(scores) == 0 {
+		return
+	}
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 24}
+	message := fmt.Sprintf("Red %d - %d Blue", scores[types.TeamRed], scores[types.TeamBlue])
+	width, _ := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(self.config.ScreenWidth)/2-width/2, 60)
+	text.Draw(screen, message, font, opts)
+}
+
+// Draws the time left in the match at the top of the screen, in red for the
+// last ten seconds.
+func (self *ArenaScene) drawMatchTimer(screen *ebiten.Image) {
+	if self.matchEndsWhen.IsZero() {
+		return
+	}
+
+	remaining := max(time.Until(self.matchEndsWhen), 0).Round(time.Second)
+	message := fmt.Sprintf("%d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 48}
+	width, _ := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(self.config.ScreenWidth)/2-width/2, 8)
+	if remaining <= 10*time.Second {
+		opts.ColorScale.Scale(1, 0.3, 0.3, 1)
+	}
+	text.Draw(screen, message, font, opts)
+}
+
 func (self *ArenaScene) showLeaderboard(screen *ebiten.Image) {
 	// Overlay background
 	overlay := ebiten.NewImage(self.config.ScreenWidth, self.config.ScreenHeight)
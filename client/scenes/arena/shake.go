@@ -0,0 +1,65 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"math"
+	"math/rand"
+)
+
+const (
+	// Shakes add up when many hits land at once, but never past this.
+	maxShakeIntensity = 25
+
+	// Events further than this from the camera don't shake it at all.
+	shakeRange = 800
+)
+
+// Jitters the camera for a few frames, fading out as it goes.
+type screenShake struct {
+	intensity   float64
+	frames      int
+	totalFrames int
+}
+
+// Adds a shake on top of the one still going, within the maximum intensity.
+func (self *screenShake) add(frames int, intensity float64) {
+	self.intensity = math.Min(self.current()+intensity, maxShakeIntensity)
+	self.frames = max(self.frames, frames)
+	self.totalFrames = self.frames
+}
+
+// The intensity of the shake for the current frame.
+func (self *screenShake) current() float64 {
+	if self.frames <= 0 {
+		return 0
+	}
+	return self.intensity * float64(self.frames) / float64(self.totalFrames)
+}
+
+// Returns the offset of the camera for this frame and moves on to the next.
+func (self *screenShake) offset() (float64, float64) {
+	intensity := self.current()
+	if intensity == 0 {
+		return 0, 0
+	}
+	self.frames -= 1
+	return (rand.Float64()*2 - 1) * intensity, (rand.Float64()*2 - 1) * intensity
+}
+
+func (self *ArenaScene) startShake(frames int, intensity float64) {
+	self.shake.add(frames, intensity)
+}
+
+// Shakes the camera for something that happened at the given position, less
+// the further it is from what the camera is looking at.
+func (self *ArenaScene) startShakeAt(position *component.PositionData, frames int, intensity float64) {
+	target, ok := self.cameraTarget()
+	if !ok {
+		return
+	}
+
+	distance := math.Hypot(position.X-target.X, position.Y-target.Y)
+	if falloff := 1 - distance/shakeRange; falloff > 0 {
+		self.startShake(frames, intensity*falloff)
+	}
+}
@@ -0,0 +1,92 @@
+package arena
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+)
+
+// How many snapshots are kept around for deltas to be applied onto, at least
+// as many as the server keeps.
+const snapshotHistorySize = 32
+
+// The players of the last few snapshots we applied, by sequence.
+type snapshotHistory struct {
+	players map[uint32][]messages.PlayerData
+}
+
+func (self *snapshotHistory) record(sequence uint32, players []messages.PlayerData) {
+	if self.players == nil {
+		self.players = make(map[uint32][]messages.PlayerData)
+	}
+	self.players[sequence] = players
+
+	for old := range self.players {
+		if old+snapshotHistorySize <= sequence {
+			delete(self.players, old)
+		}
+	}
+}
+
+// Rebuilds the players of the full snapshot the delta stands for, if we
+// still have its baseline.
+func (self *snapshotHistory) resolve(delta messages.WorldSnapshotDelta) ([]messages.PlayerData, bool) {
+	baseline, ok := self.players[delta.Baseline]
+	if !ok {
+		return nil, false
+	}
+
+	removed := make(map[types.PlayerId]bool, len(delta.RemovedPlayers))
+	for _, playerId := range delta.RemovedPlayers {
+		removed[playerId] = true
+	}
+
+	index := make(map[types.PlayerId]int, len(baseline))
+	players := make([]messages.PlayerData, 0, len(baseline))
+	for _, data := range baseline {
+		if removed[data.PlayerId] {
+			continue
+		}
+		index[data.PlayerId] = len(players)
+		players = append(players, data)
+	}
+
+	for _, change := range delta.Players {
+		i, ok := index[change.PlayerId]
+		if !ok {
+			i = len(players)
+			players = append(players, messages.PlayerData{})
+		}
+		change.ApplyTo(&players[i])
+	}
+	return players, true
+}
+
+func (self *ArenaScene) applyFullSnapshot(snapshot messages.WorldSnapshot) {
+	self.snapshots.record(snapshot.Sequence, snapshot.Players)
+	self.applyWorldSnapshot(snapshot)
+	self.acknowledgeSnapshot(snapshot.Sequence)
+}
+
+func (self *ArenaScene) applyDeltaSnapshot(delta messages.WorldSnapshotDelta) {
+	players, ok := self.snapshots.resolve(delta)
+	if !ok {
+		// Acknowledging nothing makes the server send a full snapshot next.
+		self.acknowledgeSnapshot(0)
+		return
+	}
+
+	self.snapshots.record(delta.Sequence, players)
+	self.applyWorldSnapshot(messages.WorldSnapshot{
+		Sequence:  delta.Sequence,
+		Players:   players,
+		Asteroids: delta.Asteroids,
+		PowerUps:  delta.PowerUps,
+		Missiles:  delta.Missiles,
+	})
+	self.acknowledgeSnapshot(delta.Sequence)
+}
+
+func (self *ArenaScene) acknowledgeSnapshot(sequence uint32) {
+	self.sendMessage(rpc.NewBaseMessage(messages.AcknowledgeSnapshot{Sequence: sequence}))
+}
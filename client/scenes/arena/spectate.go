@@ -0,0 +1,112 @@
+package arena
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"fmt"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Spectators and dead players can watch the other players.
+func (self *ArenaScene) isSpectating() bool {
+	return self.isSpectator || !self.isAlive
+}
+
+// Returns the position the camera should follow, if any.
+func (self *ArenaScene) cameraTarget() (component.PositionData, bool) {
+	if self.isSpectating() {
+		if self.isFreeCamera {
+			return self.freeCamera, true
+		}
+		if spectated := self.simulation.FindCorrespondingPlayer(self.spectatedId); spectated != nil && component.Player.Get(spectated).IsAlive {
+			return component.Position.GetValue(spectated), true
+		}
+	}
+
+	if self.player != nil {
+		return component.Position.GetValue(self.player), true
+	}
+	return component.PositionData{}, false
+}
+
+func (self *ArenaScene) handleSpectatorInput() {
+	// Spectators start by following whoever is alive.
+	spectated := self.simulation.FindCorrespondingPlayer(self.spectatedId)
+	isSpectatedAlive := spectated != nil && component.Player.Get(spectated).IsAlive
+
+	// Dead players can give up their ship to keep watching instead.
+	if !self.isSpectator && !self.chat.isTyping && inpututil.IsKeyJustPressed(ebiten.KeyJ) {
+		self.joinAsSpectator()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		self.isFreeCamera = false
+		self.spectateNextPlayer()
+	} else if !self.isFreeCamera && (self.isSpectator || self.isEliminated()) && !isSpectatedAlive {
+		self.spectateNextPlayer()
+	}
+
+	self.handleFreeCameraInput()
+}
+
+// Tells the server to take our ship away, we watch the game like any other
+// spectator from then on, even after reconnecting.
+func (self *ArenaScene) joinAsSpectator() {
+	self.isSpectator = true
+	self.sendMessage(rpc.NewBaseMessage(messages.JoinAsSpectator{}))
+}
+
+// Moves the camera to the next living player ordered by id.
+func (self *ArenaScene) spectateNextPlayer() {
+	ids := []types.PlayerId{}
+
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		if playerData.IsAlive && playerData.IsConnected && playerData.Id != self.playerId {
+			ids = append(ids, playerData.Id)
+		}
+	}
+
+	if len(ids) == 0 {
+		self.spectatedId = types.InvalidPlayerId
+		return
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if id > self.spectatedId {
+			self.spectatedId = id
+			return
+		}
+	}
+	self.spectatedId = ids[0]
+}
+
+func (self *ArenaScene) drawSpectatorHud(screen *ebiten.Image) {
+	message := "Press Tab To Spectate"
+	if self.isFreeCamera {
+		message = "Free Camera, Press Tab To Follow A Player"
+	} else if spectated := self.simulation.FindCorrespondingPlayer(self.spectatedId); spectated != nil {
+		message = fmt.Sprintf("Spectating %s, Press Tab To Switch", component.Player.Get(spectated).Name)
+	}
+	if !self.isSpectator {
+		message += ", J To Stop Playing"
+	}
+
+	font := &text.GoTextFace{Source: assets.Munro, Size: 24}
+	width, _ := text.Measure(message, font, 12)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(float64(self.config.ScreenWidth)/2-width/2, 20)
+	text.Draw(screen, message, font, opts)
+}
@@ -0,0 +1,100 @@
+package arena
+
+import (
+	"astro-blasters/rpc"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Counts the traffic with the server along with the messages we lost, either
+// because reading from the connection failed or because they could not be
+// decoded.
+type networkStats struct {
+	mutex sync.Mutex
+
+	readErrors int
+
+	// Indexed by message type so that a message that keeps failing stands
+	// out from the occasional glitch.
+	decodeErrors map[string]int
+
+	messagesIn  int
+	messagesOut int
+
+	// Messages per second over the last full second.
+	lastSample   time.Time
+	sampledIn    int
+	sampledOut   int
+	messageInPS  int
+	messageOutPS int
+
+	roundTripTime time.Duration
+}
+
+func (self *networkStats) recordReadError(err error, logger *slog.Logger) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.readErrors++
+	logger.Debug("Failed to read from the server", "err", err)
+}
+
+func (self *networkStats) recordDecodeError(err *rpc.DecodeError, logger *slog.Logger) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.decodeErrors == nil {
+		self.decodeErrors = make(map[string]int)
+	}
+	self.decodeErrors[err.MessageType]++
+
+	logger.Debug("Dropped a message", "type", err.MessageType, "count", self.decodeErrors[err.MessageType], "err", err)
+}
+
+func (self *networkStats) recordMessageIn() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.messagesIn++
+}
+
+func (self *networkStats) recordMessageOut() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.messagesOut++
+}
+
+func (self *networkStats) recordRoundTrip(sentAt time.Time) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.roundTripTime = time.Since(sentAt)
+}
+
+// Refreshes the message rates once a second has passed since the last sample.
+func (self *networkStats) sample() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	elapsed := time.Since(self.lastSample)
+	if elapsed < time.Second {
+		return
+	}
+
+	self.messageInPS = int(float64(self.messagesIn-self.sampledIn) / elapsed.Seconds())
+	self.messageOutPS = int(float64(self.messagesOut-self.sampledOut) / elapsed.Seconds())
+	self.sampledIn = self.messagesIn
+	self.sampledOut = self.messagesOut
+	self.lastSample = time.Now()
+}
+
+// Returns how many messages were dropped in total.
+func (self *networkStats) droppedMessages() int {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	dropped := self.readErrors
+	for _, count := range self.decodeErrors {
+		dropped += count
+	}
+	return dropped
+}
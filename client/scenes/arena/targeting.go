@@ -0,0 +1,90 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	selfMarkerRadius = 30
+
+	// Half the size of the reticle and the length of its corners.
+	reticleSize   = 34
+	reticleCorner = 10
+)
+
+var (
+	selfMarkerColor = color.RGBA{80, 220, 120, 160}
+	reticleColor    = color.RGBA{255, 70, 70, 220}
+)
+
+// Circles our own ship so that it is easy to find in a crowd.
+func (self *ArenaScene) drawSelfMarker(screen *ebiten.Image, position *component.PositionData) {
+	x := float32(position.X + self.camera.X + 4)
+	y := float32(position.Y + self.camera.Y + 4)
+	vector.StrokeCircle(screen, x, y, selfMarkerRadius, 2, selfMarkerColor, true)
+}
+
+// Returns the closest living ship we are allowed to shoot at.
+func (self *ArenaScene) nearestEnemy() (component.PositionData, bool) {
+	if self.player == nil {
+		return component.PositionData{}, false
+	}
+
+	own := component.Player.Get(self.player)
+	ownPosition := component.Position.Get(self.player)
+
+	var nearest component.PositionData
+	found := false
+	nearestDistance := math.Inf(1)
+
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		data := component.Player.Get(player)
+		if data.Id == self.playerId || !data.IsAlive || !data.IsConnected {
+			continue
+		}
+		if data.Team != types.TeamNone && data.Team == own.Team {
+			continue
+		}
+
+		position := component.Position.GetValue(player)
+		if player.HasComponent(component.Interpolation) {
+			position = component.Interpolation.Get(player).Rendered(position)
+		}
+
+		distance := math.Hypot(position.X-ownPosition.X, position.Y-ownPosition.Y)
+		if distance < nearestDistance {
+			nearest, nearestDistance, found = position, distance, true
+		}
+	}
+	return nearest, found
+}
+
+// Draws the corners of a square around the closest enemy.
+func (self *ArenaScene) drawReticle(screen *ebiten.Image) {
+	if !self.isAlive {
+		return
+	}
+
+	target, ok := self.nearestEnemy()
+	if !ok {
+		return
+	}
+
+	x := float32(target.X + self.camera.X + 4)
+	y := float32(target.Y + self.camera.Y + 4)
+
+	for _, corner := range [][2]float32{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		cornerX := x + corner[0]*reticleSize
+		cornerY := y + corner[1]*reticleSize
+		vector.StrokeLine(screen, cornerX, cornerY, cornerX-corner[0]*reticleCorner, cornerY, 2, reticleColor, true)
+		vector.StrokeLine(screen, cornerX, cornerY, cornerX, cornerY-corner[1]*reticleCorner, 2, reticleColor, true)
+	}
+}
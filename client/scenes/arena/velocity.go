@@ -0,0 +1,54 @@
+package arena
+
+import (
+	"astro-blasters/game/component"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// The arrow reaches as far as the ship drifts in this many seconds.
+	velocityArrowLookahead = 0.3
+
+	// Ships slower than this are considered stationary and get no arrow.
+	velocityArrowMinSpeed = 40
+
+	velocityArrowHeadSize = 8
+)
+
+var velocityArrowColor = color.RGBA{120, 200, 255, 180}
+
+// Points from our ship in the direction it is drifting, longer the faster it
+// goes, so that momentum is easier to anticipate.
+func (self *ArenaScene) drawVelocityArrow(screen *ebiten.Image) {
+	if self.player == nil || !self.isAlive {
+		return
+	}
+
+	position := component.Position.Get(self.player)
+	speed := position.Speed()
+	if speed < velocityArrowMinSpeed {
+		return
+	}
+
+	dx := position.VelocityX / speed
+	dy := position.VelocityY / speed
+
+	// Start outside the marker around the ship.
+	x := position.X + self.camera.X + 4
+	y := position.Y + self.camera.Y + 4
+	startX, startY := x+dx*selfMarkerRadius, y+dy*selfMarkerRadius
+	endX, endY := startX+dx*speed*velocityArrowLookahead, startY+dy*speed*velocityArrowLookahead
+	vector.StrokeLine(screen, float32(startX), float32(startY), float32(endX), float32(endY), 2, velocityArrowColor, true)
+
+	angle := math.Atan2(dy, dx)
+	for _, side := range []float64{-1, 1} {
+		headAngle := angle + math.Pi - side*math.Pi/6
+		headX := endX + math.Cos(headAngle)*velocityArrowHeadSize
+		headY := endY + math.Sin(headAngle)*velocityArrowHeadSize
+		vector.StrokeLine(screen, float32(endX), float32(endY), float32(headX), float32(headY), 2, velocityArrowColor, true)
+	}
+}
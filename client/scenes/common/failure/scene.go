@@ -41,6 +41,7 @@ func (self *FailureScene) Draw(screen *ebiten.Image) {
 
 	drawText(screen, self.error.Error(), font, 30, float64(self.config.ScreenWidth)/2, 275, 10, [4]float32{255, 255, 255, 255})
 	if self.visible {
+		drawText(screen, "Press M To Return to the Menu", font, 30, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-340, 10, [4]float32{255, 255, 255, 255})
 		drawText(screen, "Press C To Close the Game", font, 30, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-300, 10, [4]float32{255, 255, 255, 255})
 	}
 }
@@ -52,6 +53,13 @@ func (self *FailureScene) Update(controller *scenes.AppController) {
 	default:
 	}
 
+	if ebiten.IsKeyPressed(ebiten.KeyM) {
+		self.once.Do(
+			func() {
+				controller.ReturnToMenu()
+			})
+	}
+
 	if ebiten.IsKeyPressed(ebiten.KeyC) {
 		self.once.Do(
 			func() {
@@ -64,6 +72,10 @@ func (self *FailureScene) Configure(controller *scenes.AppController) error {
 	return nil
 }
 
+func (self *FailureScene) Dispose() {
+	self.ticker.Stop()
+}
+
 func drawText(screen *ebiten.Image, msg string, fontface text.GoTextFace, fontSize float64, x, y float64, lineSpacing int, colorScale [4]float32) {
 	fontface.Size = fontSize
 	width, height := text.Measure(msg, &fontface, 10)
@@ -0,0 +1,124 @@
+package connecting
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/config"
+	"astro-blasters/client/network"
+	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/common"
+	"astro-blasters/client/scenes/common/failure"
+	"astro-blasters/client/scenes/lobby"
+	"astro-blasters/server/messages"
+	"context"
+	"errors"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const spinnerDots = 8
+
+type connectionResult struct {
+	connection *websocket.Conn
+	response   messages.ConnectionHandshakeResponse
+	err        error
+}
+
+// Shows a spinner while connecting to the server in the background, so the
+// window keeps responding during the handshake.
+type ConnectingScene struct {
+	config     *config.ClientConfig
+	background *common.Background
+	once       sync.Once
+	frames     int
+
+	handshake messages.ConnectionHandshake
+	result    chan connectionResult
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewConnectingScene(config *config.ClientConfig, handshake messages.ConnectionHandshake) *ConnectingScene {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ConnectingScene{
+		config:     config,
+		background: common.NewBackground(config.ScreenWidth, config.ScreenHeight),
+		handshake:  handshake,
+		result:     make(chan connectionResult, 1),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (self *ConnectingScene) Configure(controller *scenes.AppController) error {
+	go func() {
+		connection, response, err := network.Connect(self.ctx, self.config.Endpoint(), self.handshake)
+		self.result <- connectionResult{connection: connection, response: response, err: err}
+	}()
+	return nil
+}
+
+// Gives up on connecting if we leave before it is done.
+func (self *ConnectingScene) Dispose() {
+	self.cancel()
+}
+
+func (self *ConnectingScene) Draw(screen *ebiten.Image) {
+	screen.Clear()
+	screen.DrawImage(self.background.Image, nil)
+
+	centerX := float64(self.config.ScreenWidth) / 2
+	centerY := float64(self.config.ScreenHeight) / 2
+
+	// The brightest dot goes around the circle.
+	active := (self.frames / 6) % spinnerDots
+	for i := range spinnerDots {
+		angle := 2 * math.Pi * float64(i) / spinnerDots
+		x := centerX + 40*math.Cos(angle)
+		y := centerY + 40*math.Sin(angle)
+
+		alpha := uint8(80)
+		if i == active {
+			alpha = 255
+		}
+		vector.DrawFilledCircle(screen, float32(x), float32(y), 6, color.RGBA{alpha, alpha, alpha, alpha}, false)
+	}
+
+	font := &text.GoTextFace{Source: assets.MunroNarrow, Size: 30}
+	message := "Connecting to the Server"
+	width, _ := text.Measure(message, font, 10)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(centerX-width/2, centerY+80)
+	text.Draw(screen, message, font, opts)
+}
+
+func (self *ConnectingScene) Update(controller *scenes.AppController) {
+	self.frames++
+
+	select {
+	case result := <-self.result:
+		self.once.Do(func() {
+			var full *network.ServerFullError
+			if errors.As(result.err, &full) {
+				self.config.Logger.Info("The server is full", "url", self.config.ServerWebsocketURL, "players", full.MaxPlayers)
+				controller.ChangeScene(NewServerFullScene(self.config, self.handshake, full.MaxPlayers))
+				return
+			}
+			if result.err != nil {
+				self.config.Logger.Error("Failed to connect to the server", "url", self.config.ServerWebsocketURL, "err", result.err)
+				controller.ChangeScene(failure.NewFailureScene(self.config, result.err))
+				return
+			}
+			self.config.Logger.Info("Connected to the server", "url", self.config.ServerWebsocketURL, "player", result.response.PlayerId)
+			controller.ChangeScene(lobby.NewLobbyScene(self.config, self.handshake, result.connection, result.response))
+		})
+	default:
+	}
+}
@@ -0,0 +1,98 @@
+package connecting
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/config"
+	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/common"
+	"astro-blasters/server/messages"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Shown when every ship on the server is taken, offering to try again or to
+// watch the game as a spectator until there's room.
+type ServerFullScene struct {
+	config     *config.ClientConfig
+	background *common.Background
+	handshake  messages.ConnectionHandshake
+	maxPlayers int
+	ticker     *time.Ticker
+	visible    bool
+	once       sync.Once
+}
+
+func NewServerFullScene(config *config.ClientConfig, handshake messages.ConnectionHandshake, maxPlayers int) *ServerFullScene {
+	return &ServerFullScene{
+		config:     config,
+		background: common.NewBackground(config.ScreenWidth, config.ScreenHeight),
+		handshake:  handshake,
+		maxPlayers: maxPlayers,
+		visible:    true,
+		ticker:     time.NewTicker(500 * time.Millisecond),
+	}
+}
+
+func (self *ServerFullScene) Draw(screen *ebiten.Image) {
+	screen.Clear()
+	screen.DrawImage(self.background.Image, nil)
+
+	centerX := float64(self.config.ScreenWidth) / 2
+	centerY := float64(self.config.ScreenHeight) / 2
+
+	drawCentered(screen, "The Server is Full", 50, centerX, centerY-120)
+	drawCentered(screen, fmt.Sprintf("All %d ships are taken", self.maxPlayers), 30, centerX, centerY-60)
+	if self.visible {
+		drawCentered(screen, "Press R To Try Again", 30, centerX, centerY+40)
+		drawCentered(screen, "Press S To Spectate", 30, centerX, centerY+80)
+		drawCentered(screen, "Press M To Return to the Menu", 30, centerX, centerY+120)
+	}
+}
+
+func (self *ServerFullScene) Update(controller *scenes.AppController) {
+	select {
+	case <-self.ticker.C:
+		self.visible = !self.visible
+	default:
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyR) {
+		self.once.Do(func() {
+			controller.ChangeScene(NewConnectingScene(self.config, self.handshake))
+		})
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyS) {
+		self.once.Do(func() {
+			handshake := messages.ConnectionHandshake{PlayerName: self.handshake.PlayerName, IsSpectator: true}
+			controller.ChangeScene(NewConnectingScene(self.config, handshake))
+		})
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyM) {
+		self.once.Do(func() {
+			controller.ReturnToMenu()
+		})
+	}
+}
+
+func (self *ServerFullScene) Configure(controller *scenes.AppController) error {
+	return nil
+}
+
+func (self *ServerFullScene) Dispose() {
+	self.ticker.Stop()
+}
+
+func drawCentered(screen *ebiten.Image, message string, size float64, x, y float64) {
+	font := &text.GoTextFace{Source: assets.MunroNarrow, Size: size}
+	width, height := text.Measure(message, font, 10)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(x-width/2, y-height/2)
+	text.Draw(screen, message, font, opts)
+}
@@ -0,0 +1,112 @@
+package leaderboard
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/config"
+	"astro-blasters/client/network"
+	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/common"
+	"astro-blasters/server/messages"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type fetchResult struct {
+	entries []messages.PlayerStats
+	err     error
+}
+
+// Shows the best players of all time, fetched from the server in the
+// background.
+type LeaderboardScene struct {
+	config     *config.ClientConfig
+	background *common.Background
+	once       sync.Once
+
+	result  chan fetchResult
+	entries []messages.PlayerStats
+	err     error
+	loaded  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewLeaderboardScene(config *config.ClientConfig) *LeaderboardScene {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LeaderboardScene{
+		config:     config,
+		background: common.NewBackground(config.ScreenWidth, config.ScreenHeight),
+		result:     make(chan fetchResult, 1),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (self *LeaderboardScene) Configure(controller *scenes.AppController) error {
+	go func() {
+		entries, err := network.FetchLeaderboard(self.ctx, self.config.Endpoint())
+		self.result <- fetchResult{entries: entries, err: err}
+	}()
+	return nil
+}
+
+func (self *LeaderboardScene) Dispose() {
+	self.cancel()
+}
+
+func (self *LeaderboardScene) Update(controller *scenes.AppController) {
+	select {
+	case result := <-self.result:
+		self.entries = result.entries
+		self.err = result.err
+		self.loaded = true
+	default:
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyEscape) || ebiten.IsKeyPressed(ebiten.KeyM) {
+		self.once.Do(func() {
+			controller.ReturnToMenu()
+		})
+	}
+}
+
+func (self *LeaderboardScene) Draw(screen *ebiten.Image) {
+	screen.Clear()
+	screen.DrawImage(self.background.Image, nil)
+
+	centerX := float64(self.config.ScreenWidth) / 2
+	drawText(screen, "All-Time Leaderboard", 60, centerX, 80)
+
+	switch {
+	case !self.loaded:
+		drawText(screen, "Loading...", 30, centerX, 250)
+	case self.err != nil:
+		drawText(screen, self.err.Error(), 24, centerX, 250)
+	case len(self.entries) == 0:
+		drawText(screen, "Nobody has played yet", 30, centerX, 250)
+	}
+
+	if len(self.entries) > 0 {
+		drawText(screen, "Player  Wins  Kills  Deaths", 28, centerX, 170)
+	}
+	for i, entry := range self.entries {
+		line := fmt.Sprintf("%d. %s  %d  %d  %d", i+1, entry.PlayerName, entry.Wins, entry.Kills, entry.Deaths)
+		drawText(screen, line, 26, centerX, float64(220+i*40))
+	}
+
+	drawText(screen, "Press M To Return to the Menu", 24, centerX, float64(self.config.ScreenHeight)-60)
+}
+
+func drawText(screen *ebiten.Image, message string, size float64, x, y float64) {
+	font := &text.GoTextFace{Source: assets.MunroNarrow, Size: size}
+	width, height := text.Measure(message, font, 10)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(x-width/2, y-height/2)
+	text.Draw(screen, message, font, opts)
+}
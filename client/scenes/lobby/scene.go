@@ -0,0 +1,234 @@
+package lobby
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/config"
+	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/arena"
+	"astro-blasters/client/scenes/common"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type lobbyPlayer struct {
+	name    string
+	isReady bool
+}
+
+type LobbyScene struct {
+	config     *config.ClientConfig
+	background *common.Background
+	once       sync.Once
+	visible    bool
+	ticker     *time.Ticker
+
+	playerName  string
+	isSpectator bool
+	lastPing    time.Time
+	connection  *websocket.Conn
+	response    messages.ConnectionHandshakeResponse
+
+	// Guards the fields below since they are updated while receiving
+	// messages from the server.
+	mutex          sync.Mutex
+	players        map[types.PlayerId]lobbyPlayer
+	hasGameStarted bool
+}
+
+// Takes over the connection established with the given handshake.
+func NewLobbyScene(config *config.ClientConfig, handshake messages.ConnectionHandshake, connection *websocket.Conn, response messages.ConnectionHandshakeResponse) *LobbyScene {
+	return &LobbyScene{
+		isSpectator: handshake.IsSpectator,
+		connection:  connection,
+		response:    response,
+		config:      config,
+		background:  common.NewBackground(config.ScreenWidth, config.ScreenHeight),
+		visible:     true,
+		ticker:      time.NewTicker(500 * time.Millisecond),
+		playerName:  handshake.PlayerName,
+		players:     make(map[types.PlayerId]lobbyPlayer),
+	}
+}
+
+func (self *LobbyScene) Configure(controller *scenes.AppController) error {
+	response := self.response
+
+	// Spectators have no reason to wait in the lobby.
+	self.hasGameStarted = response.HasGameStarted || self.isSpectator
+
+	for _, player := range response.PlayerData {
+		self.players[player.PlayerId] = lobbyPlayer{name: player.PlayerName, isReady: player.IsReady}
+	}
+
+	if !self.hasGameStarted {
+		go self.receiveServerUpdates()
+	}
+	return nil
+}
+
+func (self *LobbyScene) Dispose() {
+	self.ticker.Stop()
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	// The arena takes over the connection once the game starts.
+	if self.connection != nil && !self.hasGameStarted {
+		go self.connection.Close(websocket.StatusNormalClosure, "left the lobby")
+	}
+}
+
+func (self *LobbyScene) Draw(screen *ebiten.Image) {
+	screen.Clear()
+	screen.DrawImage(self.background.Image, nil)
+
+	fontface := text.GoTextFace{Source: assets.MunroNarrow}
+	lineSpacing := 10
+
+	imageWidth := assets.Borders.Image.Bounds().Dx()
+	self.drawTransformedImage(screen, assets.Borders.GetTile(assets.TileIndex{X: 0, Y: 3}), 25, 7, 0, (float64(self.config.ScreenWidth-imageWidth)/3)+55, 50)
+	self.drawText(screen, "Lobby", fontface, 50, float64(self.config.ScreenWidth)/2, 105, lineSpacing, color.White)
+
+	self.mutex.Lock()
+	ids := make([]types.PlayerId, 0, len(self.players))
+	for id := range self.players {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for i, id := range ids {
+		player := self.players[id]
+		y := float64(220 + i*45)
+
+		status, statusColor := "Waiting", color.RGBA{200, 200, 200, 255}
+		if player.isReady {
+			status, statusColor = "Ready", color.RGBA{0, 255, 0, 255}
+		}
+
+		name := player.name
+		if id == self.response.PlayerId {
+			name = fmt.Sprintf("%s (you)", name)
+		}
+
+		self.drawText(screen, name, fontface, 35, 400, y, lineSpacing, color.White)
+		self.drawText(screen, status, fontface, 35, 700, y, lineSpacing, statusColor)
+	}
+	self.mutex.Unlock()
+
+	if self.visible {
+		self.drawText(screen, "Press R To Toggle Ready", fontface, 40, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-110, lineSpacing, color.White)
+	}
+}
+
+func (self *LobbyScene) drawTransformedImage(screen *ebiten.Image, image *ebiten.Image, scaleX, scaleY, rotate, translateX, translateY float64) {
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(scaleX, scaleY)
+	opts.GeoM.Rotate(rotate) // Rotation in radians; use 0 for no rotation
+	opts.GeoM.Translate(translateX, translateY)
+	screen.DrawImage(image, opts)
+}
+
+func (self *LobbyScene) drawText(screen *ebiten.Image, msg string, fontface text.GoTextFace, fontSize float64, x, y float64, lineSpacing int, clr color.Color) {
+	fontface.Size = fontSize
+	width, height := text.Measure(msg, &fontface, 10)
+
+	opts := &text.DrawOptions{}
+	opts.LineSpacing = float64(lineSpacing)
+	opts.GeoM.Translate(-width/2, -height/2)
+	opts.GeoM.Translate(x, y)
+	opts.ColorScale.ScaleWithColor(clr)
+	text.Draw(screen, msg, &fontface, opts)
+}
+
+func (self *LobbyScene) Update(controller *scenes.AppController) {
+	// Toggle visibility every tick
+	select {
+	case <-self.ticker.C:
+		self.visible = !self.visible
+	default:
+	}
+
+	self.mutex.Lock()
+	hasGameStarted := self.hasGameStarted
+	isReady := self.players[self.response.PlayerId].isReady
+	self.mutex.Unlock()
+
+	if hasGameStarted {
+		self.once.Do(
+			func() {
+				controller.ChangeScene(arena.NewArenaScene(self.config, self.playerName, self.isSpectator, self.connection, self.response))
+			})
+		return
+	}
+
+	// Let the server know we are still here while waiting.
+	if time.Since(self.lastPing) >= time.Second {
+		self.lastPing = time.Now()
+		rpc.WriteMessage(context.Background(), self.connection, rpc.NewBaseMessage(messages.Ping{SentAt: self.lastPing.UnixNano()}))
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		message := rpc.NewBaseMessage(messages.RegisterPlayerReady{IsReady: !isReady})
+		rpc.WriteMessage(context.Background(), self.connection, message)
+	}
+}
+
+// Keeps track of the players in the lobby until the server starts the game.
+func (self *LobbyScene) receiveServerUpdates() {
+	for {
+		var message rpc.BaseMessage
+		if err := rpc.ReceiveMessage(context.Background(), self.connection, &message); err != nil {
+			var decodeErr *rpc.DecodeError
+			if errors.As(err, &decodeErr) {
+				continue
+			}
+			return
+		}
+
+		self.mutex.Lock()
+		switch message.MessageType {
+		case "EventPlayerConnected":
+			var event messages.EventPlayerConnected
+			if err := rpc.DecodeExpectedMessage(message, &event); err == nil {
+				self.players[event.PlayerId] = lobbyPlayer{name: event.PlayerName}
+			}
+		case "EventPlayerDisconnected":
+			var event messages.EventPlayerDisconnected
+			if err := rpc.DecodeExpectedMessage(message, &event); err == nil {
+				delete(self.players, event.PlayerId)
+			}
+		case "EventPlayerReady":
+			var event messages.EventPlayerReady
+			if err := rpc.DecodeExpectedMessage(message, &event); err == nil {
+				if player, ok := self.players[event.PlayerId]; ok {
+					player.isReady = event.IsReady
+					self.players[event.PlayerId] = player
+				}
+			}
+		case "EventGameStart":
+			var event messages.EventGameStart
+			if err := rpc.DecodeExpectedMessage(message, &event); err == nil {
+				// The arena starts from the state of the world when the game
+				// started.
+				self.response.PlayerData = event.PlayerData
+				self.hasGameStarted = true
+				self.mutex.Unlock()
+				return
+			}
+		}
+		self.mutex.Unlock()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"astro-blasters/client/config"
 	"astro-blasters/client/scenes"
 	"astro-blasters/client/scenes/common"
+	"astro-blasters/client/scenes/leaderboard"
 	"astro-blasters/client/scenes/submenu"
 
 	"sync"
@@ -55,7 +56,8 @@ func (self *MenuScene) Draw(screen *ebiten.Image) {
 
 	// Draw subtext
 	if self.visible {
-		self.drawText(screen, "Press S To Start the Game", fontface, 40, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-100, lineSpacing)
+		self.drawText(screen, "Press S To Start the Game", fontface, 40, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-120, lineSpacing)
+		self.drawText(screen, "Press L To See the Leaderboard", fontface, 30, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-70, lineSpacing)
 	}
 }
 
@@ -85,9 +87,20 @@ func (self *MenuScene) Update(controller *scenes.AppController) {
 				controller.ChangeScene(submenu.NewSubMenuScene(self.config))
 			})
 	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyL) {
+		self.once.Do(
+			func() {
+				controller.ChangeScene(leaderboard.NewLeaderboardScene(self.config))
+			})
+	}
 }
 
 func (self *MenuScene) Configure(controller *scenes.AppController) error {
 	controller.ChangeMusic(assets.IntroMusic)
 	return nil
 }
+
+func (self *MenuScene) Dispose() {
+	self.ticker.Stop()
+}
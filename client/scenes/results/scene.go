@@ -0,0 +1,146 @@
+package results
+
+import (
+	"astro-blasters/assets"
+	"astro-blasters/client/config"
+	"astro-blasters/client/scenes"
+	"astro-blasters/client/scenes/common"
+	"astro-blasters/game/types"
+	"astro-blasters/server/messages"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// The number of players listed on the final scoreboard.
+const maxRows = 8
+
+// Shows the winner and the final scoreboard once a match is over.
+type ResultsScene struct {
+	config     *config.ClientConfig
+	background *common.Background
+	handshake  messages.ConnectionHandshake
+	players    []messages.PlayerData
+	ticker     *time.Ticker
+	visible    bool
+	once       sync.Once
+}
+
+// The handshake is used to reconnect to the lobby for the next match.
+func NewResultsScene(config *config.ClientConfig, handshake messages.ConnectionHandshake, players []messages.PlayerData) *ResultsScene {
+	players = append([]messages.PlayerData{}, players...)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Score > players[j].Score
+	})
+
+	return &ResultsScene{
+		config:     config,
+		background: common.NewBackground(config.ScreenWidth, config.ScreenHeight),
+		handshake:  handshake,
+		players:    players,
+		visible:    true,
+		ticker:     time.NewTicker(500 * time.Millisecond),
+	}
+}
+
+func (self *ResultsScene) Configure(controller *scenes.AppController) error {
+	return nil
+}
+
+func (self *ResultsScene) Dispose() {
+	self.ticker.Stop()
+}
+
+func (self *ResultsScene) Update(controller *scenes.AppController) {
+	select {
+	case <-self.ticker.C:
+		self.visible = !self.visible
+	default:
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyEnter) {
+		self.once.Do(func() {
+			controller.ReturnToLobby(self.handshake)
+		})
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyM) {
+		self.once.Do(func() {
+			controller.ReturnToMenu()
+		})
+	}
+}
+
+func (self *ResultsScene) Draw(screen *ebiten.Image) {
+	screen.Clear()
+	screen.DrawImage(self.background.Image, nil)
+
+	centerX := float64(self.config.ScreenWidth) / 2
+	white := [4]float32{1, 1, 1, 1}
+
+	drawText(screen, "Match Over", assets.Munro, 60, centerX, 80, white)
+	drawText(screen, self.winner(), assets.Munro, 36, centerX, 160, [4]float32{1, 0.8, 0.2, 1})
+
+	for i, player := range self.players {
+		if i >= maxRows {
+			break
+		}
+
+		line := fmt.Sprintf("%d. %s %d", i+1, player.PlayerName, player.Score)
+		if player.Team != types.TeamNone {
+			line = fmt.Sprintf("%d. %s (%s) %d", i+1, player.PlayerName, player.Team, player.Score)
+		}
+		drawText(screen, line, assets.Munro, 28, centerX, float64(240+i*40), white)
+	}
+
+	if self.visible {
+		height := float64(self.config.ScreenHeight)
+		drawText(screen, "Press Enter To Return to the Lobby", assets.Munro, 24, centerX, height-100, white)
+		drawText(screen, "Press M To Return to the Menu", assets.Munro, 24, centerX, height-65, white)
+	}
+}
+
+// Describes who won, the team with the most points in the team mode and the
+// best player otherwise.
+func (self *ResultsScene) winner() string {
+	teamScores := map[types.Team]int{}
+	for _, player := range self.players {
+		if player.Team != types.TeamNone {
+			teamScores[player.Team] += player.Score
+		}
+	}
+
+	if len(teamScores) > 0 {
+		red, blue := teamScores[types.TeamRed], teamScores[types.TeamBlue]
+		switch {
+		case red > blue:
+			return fmt.Sprintf("Team Red wins %d - %d", red, blue)
+		case blue > red:
+			return fmt.Sprintf("Team Blue wins %d - %d", blue, red)
+		default:
+			return fmt.Sprintf("It's a draw %d - %d", red, blue)
+		}
+	}
+
+	if len(self.players) == 0 {
+		return "Nobody played"
+	}
+	if len(self.players) > 1 && self.players[0].Score == self.players[1].Score {
+		return "It's a draw"
+	}
+	return fmt.Sprintf("%s wins", self.players[0].PlayerName)
+}
+
+func drawText(screen *ebiten.Image, message string, source *text.GoTextFaceSource, size float64, x, y float64, colorScale [4]float32) {
+	font := &text.GoTextFace{Source: source, Size: size}
+	width, height := text.Measure(message, font, 10)
+
+	opts := &text.DrawOptions{}
+	opts.GeoM.Translate(x-width/2, y-height/2)
+	opts.ColorScale.Scale(colorScale[0], colorScale[1], colorScale[2], colorScale[3])
+	text.Draw(screen, message, font, opts)
+}
@@ -1,6 +1,8 @@
 package scenes
 
 import (
+	"astro-blasters/server/messages"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -8,12 +10,19 @@ type Scene interface {
 	Draw(screen *ebiten.Image)
 	Update(controller *AppController)
 	Configure(controller *AppController) error
+
+	// Called when the app moves on to another scene, to release anything the
+	// scene still holds on to.
+	Dispose()
 }
 
 type app interface {
 	ChangeScene(scenes Scene)
 	ChangeMusic(data []byte)
 	PlaySfx(data []byte)
+	PlayThruster(isThrusting bool)
+	ReturnToMenu()
+	ReturnToLobby(handshake messages.ConnectionHandshake)
 }
 
 type AppController struct {
@@ -35,3 +44,17 @@ func (self *AppController) ChangeMusic(data []byte) {
 func (self *AppController) PlaySfx(data []byte) {
 	self.app.PlaySfx(data)
 }
+
+// Loops the thruster sound for as long as the ship is thrusting.
+func (self *AppController) PlayThruster(isThrusting bool) {
+	self.app.PlayThruster(isThrusting)
+}
+
+func (self *AppController) ReturnToMenu() {
+	self.app.ReturnToMenu()
+}
+
+// Connects to the server again and waits in the lobby for the next game.
+func (self *AppController) ReturnToLobby(handshake messages.ConnectionHandshake) {
+	self.app.ReturnToLobby(handshake)
+}
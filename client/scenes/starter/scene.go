@@ -4,8 +4,11 @@ import (
 	"astro-blasters/assets"
 	"astro-blasters/client/config"
 	"astro-blasters/client/scenes"
-	"astro-blasters/client/scenes/arena"
 	"astro-blasters/client/scenes/common"
+	"astro-blasters/client/scenes/connecting"
+	"astro-blasters/game"
+	"astro-blasters/game/types"
+	"astro-blasters/server/messages"
 	"fmt"
 	"image/color"
 	"sync"
@@ -26,6 +29,7 @@ type StarterScene struct {
 	ticker        *time.Ticker
 	cursorVisible bool
 	cursorTimer   time.Duration
+	shipClass     types.ShipClass
 }
 
 func NewStarterScene(config *config.ClientConfig) *StarterScene {
@@ -43,17 +47,27 @@ func (self *StarterScene) Draw(screen *ebiten.Image) {
 	self.drawTransformedImage(screen, assets.Borders.GetTile(assets.TileIndex{X: 0, Y: 1}), 60, 15, 0, 50, 185)
 
 	self.drawText(screen, "Before we take off, cadet, what should we call the brave soul leading this mission?", fontface, 27, 530, 245, lineSpacing)
-	self.drawText(screen, "Press 'Enter' to type in your username.", fontface, 27, 530, 280, lineSpacing)
+	self.drawText(screen, "Press 'Enter' to type in your username, and the arrow keys to pick a ship.", fontface, 27, 530, 280, lineSpacing)
 
 	self.drawText(screen, fmt.Sprintf("> %s", self.inputText), fontface, 30, 530, 330, lineSpacing)
 
 	self.RenderCursor(screen)
+	self.drawShipClass(screen, fontface, lineSpacing)
 
 	if self.visible {
 		self.drawText(screen, "Press Esc To Play the Game", fontface, 40, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-250, lineSpacing)
+		self.drawText(screen, "Press Tab To Spectate", fontface, 30, float64(self.config.ScreenWidth)/2, float64(self.config.ScreenHeight)-200, lineSpacing)
 	}
 }
 
+// Shows the selected ship along with its class.
+func (self *StarterScene) drawShipClass(screen *ebiten.Image, fontface text.GoTextFace, lineSpacing int) {
+	ship := game.GetShipSprite(0, types.TeamNone, self.shipClass)
+	self.drawTransformedImage(screen, ship, 4, 4, 0, float64(self.config.ScreenWidth)/2-130, 370)
+
+	self.drawText(screen, fmt.Sprintf("Ship: < %s >", self.shipClass), fontface, 27, float64(self.config.ScreenWidth)/2, 385, lineSpacing)
+}
+
 func (self *StarterScene) drawTransformedImage(screen *ebiten.Image, image *ebiten.Image, scaleX, scaleY, rotate, translateX, translateY float64) {
 	opts := &ebiten.DrawImageOptions{}
 	opts.GeoM.Scale(scaleX, scaleY)
@@ -114,6 +128,16 @@ func (self *StarterScene) Update(controller *scenes.AppController) {
 		}
 	}
 
+	// The arrow keys are free to pick a ship while not typing.
+	if !self.isFocused {
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
+			self.shipClass = (self.shipClass + 1) % types.ShipClassCount
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+			self.shipClass = (self.shipClass + types.ShipClassCount - 1) % types.ShipClassCount
+		}
+	}
+
 	// Toggle visibility every tick
 	select {
 	case <-self.ticker.C:
@@ -124,9 +148,18 @@ func (self *StarterScene) Update(controller *scenes.AppController) {
 	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
 		self.once.Do(
 			func() {
-				controller.ChangeScene(arena.NewArenaScene(self.config, self.inputText))
+				controller.ChangeScene(connecting.NewConnectingScene(self.config, messages.ConnectionHandshake{PlayerName: self.inputText, ShipClass: self.shipClass}))
+			})
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyTab) {
+		self.once.Do(
+			func() {
+				controller.ChangeScene(connecting.NewConnectingScene(self.config, messages.ConnectionHandshake{PlayerName: self.inputText, IsSpectator: true}))
 			})
 	}
 }
 
 func (self *StarterScene) Configure(controller *scenes.AppController) error { return nil }
+
+func (self *StarterScene) Dispose() { self.ticker.Stop() }
@@ -113,3 +113,7 @@ func (self *SubMenuScene) Update(controller *scenes.AppController) {
 func (self *SubMenuScene) Configure(controller *scenes.AppController) error {
 	return nil
 }
+
+func (self *SubMenuScene) Dispose() {
+	self.ticker.Stop()
+}
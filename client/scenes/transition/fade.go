@@ -0,0 +1,81 @@
+package transition
+
+import (
+	"astro-blasters/client/scenes"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Fades the last frame of the outgoing scene to black, then fades the
+// incoming scene in from black. The incoming scene is only updated once it
+// starts to show.
+type FadeTransition struct {
+	from        *ebiten.Image
+	to          scenes.Scene
+	duration    time.Duration
+	startedWhen time.Time
+}
+
+// `from` holds the last frame drawn by the outgoing scene, which should
+// already be disposed of.
+func NewFadeTransition(from *ebiten.Image, to scenes.Scene, duration time.Duration) *FadeTransition {
+	return &FadeTransition{
+		from:     from,
+		to:       to,
+		duration: duration,
+	}
+}
+
+func (self *FadeTransition) Configure(controller *scenes.AppController) error {
+	self.startedWhen = time.Now()
+	return self.to.Configure(controller)
+}
+
+func (self *FadeTransition) Dispose() {
+	self.from.Deallocate()
+	self.to.Dispose()
+}
+
+// The scene faded in, which takes over once the transition is done.
+func (self *FadeTransition) Scene() scenes.Scene {
+	return self.to
+}
+
+func (self *FadeTransition) IsDone() bool {
+	return self.progress() >= 1
+}
+
+func (self *FadeTransition) Update(controller *scenes.AppController) {
+	if self.progress() >= 0.5 {
+		self.to.Update(controller)
+	}
+}
+
+func (self *FadeTransition) Draw(screen *ebiten.Image) {
+	progress := self.progress()
+
+	// How dark the screen is, peaking halfway through.
+	var darkness float64
+	if progress < 0.5 {
+		screen.Clear()
+		screen.DrawImage(self.from, nil)
+		darkness = progress * 2
+	} else {
+		self.to.Draw(screen)
+		darkness = 2 - progress*2
+	}
+
+	width, height := screen.Bounds().Dx(), screen.Bounds().Dy()
+	vector.DrawFilledRect(screen, 0, 0, float32(width), float32(height), color.RGBA{A: uint8(255 * darkness)}, false)
+}
+
+// Returns how far along the transition is, from 0 to 1.
+func (self *FadeTransition) progress() float64 {
+	if self.duration <= 0 {
+		return 1
+	}
+	return min(float64(time.Since(self.startedWhen))/float64(self.duration), 1)
+}
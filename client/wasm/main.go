@@ -26,14 +26,17 @@ func getServerUrl() (serverUrl string, isSecure bool) {
 }
 
 func main() {
-	serverUrl, _ := getServerUrl()
-	serverWebsocketUrl := fmt.Sprintf("ws://%s/play/ws", serverUrl)
+	serverUrl, isSecure := getServerUrl()
 
-	config := config.ClientConfig{
-		ScreenWidth:        1080,
-		ScreenHeight:       720,
-		ServerWebsocketURL: serverWebsocketUrl,
+	// Pages served over HTTPS can only open secure websockets.
+	protocol := "ws"
+	if isSecure {
+		protocol = "wss"
 	}
+	serverWebsocketUrl := fmt.Sprintf("%s://%s/play/ws", protocol, serverUrl)
+
+	config := config.DefaultClientConfig()
+	config.ServerWebsocketURL = serverWebsocketUrl
 
 	app := client.NewApp(&config)
 	if err := app.Run(); err != nil {
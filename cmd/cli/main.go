@@ -3,14 +3,19 @@ package main
 import (
 	"astro-blasters/client"
 	"astro-blasters/client/config"
+	"astro-blasters/game"
+	"astro-blasters/game/types"
 	"astro-blasters/server"
+	serverConfig "astro-blasters/server/config"
 	"bytes"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -20,7 +25,29 @@ func main() {
 
 	// Server command
 	{
+		defaults := serverConfig.DefaultServerConfig()
+
+		var configPath string
 		var port int
+		var rapidFireCooldown time.Duration
+		var teamMode bool
+		var wrap bool
+		var mapWidth float64
+		var mapHeight float64
+		var matchDuration time.Duration
+		var roundBreak time.Duration
+		var keepScores bool
+		var respawnProtection time.Duration
+		var lives int
+		var tickRate int
+		var leaderboardPath string
+		var maxPlayers int
+		var bots int
+		var weaponsPath string
+		var spawnStrategy string
+		var tlsCertFile string
+		var tlsKeyFile string
+		var logLevel string
 		serverCmd := &cobra.Command{
 			Use:   "server",
 			Short: "Run the server",
@@ -55,40 +82,165 @@ func main() {
 					}
 				}
 
-				server := server.NewServer()
+				logger, err := newLogger(logLevel)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				// Flags given on the command line take precedence over the
+				// config file.
+				config := serverConfig.DefaultServerConfig()
+				config.Logger = logger
+				if configPath != "" {
+					if err := serverConfig.LoadServerConfig(configPath, &config); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+
+				override(cmd, "rapid-fire-cooldown", &config.RapidFireCooldown, rapidFireCooldown)
+				override(cmd, "teams", &config.TeamMode, teamMode)
+				override(cmd, "match-duration", &config.MatchDuration, matchDuration)
+				override(cmd, "round-break", &config.RoundBreak, roundBreak)
+				override(cmd, "keep-scores", &config.KeepScores, keepScores)
+				override(cmd, "respawn-protection", &config.RespawnProtection, respawnProtection)
+				override(cmd, "lives", &config.Lives, lives)
+				override(cmd, "tickrate", &config.TickRate, tickRate)
+				override(cmd, "leaderboard", &config.LeaderboardPath, leaderboardPath)
+				override(cmd, "max-players", &config.MaxPlayers, maxPlayers)
+				override(cmd, "bots", &config.Bots, bots)
+				override(cmd, "tls-cert", &config.TLSCertFile, tlsCertFile)
+				override(cmd, "tls-key", &config.TLSKeyFile, tlsKeyFile)
+				override(cmd, "map-width", &config.MapWidth, mapWidth)
+				override(cmd, "map-height", &config.MapHeight, mapHeight)
+
+				if cmd.Flags().Changed("spawn") {
+					if config.SpawnStrategy, err = serverConfig.ParseSpawnStrategy(spawnStrategy); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+				if cmd.Flags().Changed("weapons") {
+					if config.Weapons, err = game.LoadWeapons(weaponsPath); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+				if cmd.Flags().Changed("wrap") {
+					config.WorldMode = types.WorldClamp
+					if wrap {
+						config.WorldMode = types.WorldWrap
+					}
+				}
+
+				if err := config.Validate(); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				server := server.NewServer(&config)
 				if err := server.Start(port); err != nil {
 					fmt.Println(err)
 					os.Exit(1)
 				}
 			},
 		}
+		serverCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to a JSON file with the settings of the server, overridden by the flags given")
 		serverCmd.Flags().IntVarP(&port, "port", "p", 8080, "Port to run the server on")
+		serverCmd.Flags().StringVar(&logLevel, "log-level", "info", "The least severe messages logged, one of debug, info, warn or error")
+		serverCmd.Flags().StringVar(&weaponsPath, "weapons", "", "Path to a JSON file with the stats of the weapons, such as weapons.json")
+		serverCmd.Flags().BoolVarP(&teamMode, "teams", "t", false, "Split the players into two teams")
+		serverCmd.Flags().IntVar(&maxPlayers, "max-players", defaults.MaxPlayers, "The most players allowed in the game at once, 0 for no limit")
+		serverCmd.Flags().IntVar(&bots, "bots", 0, "The number of ships flown by the server")
+		serverCmd.Flags().IntVar(&tickRate, "tickrate", defaults.TickRate, "How many times per second the server updates the simulation")
+		serverCmd.Flags().DurationVar(&matchDuration, "match-duration", defaults.MatchDuration, "How long a match lasts, 0 for endless matches")
+		serverCmd.Flags().DurationVar(&roundBreak, "round-break", 0, "How long after a match the next round starts without going back to the lobby, 0 to go back")
+		serverCmd.Flags().BoolVar(&keepScores, "keep-scores", false, "Carry the scores over from one round to the next")
+		serverCmd.Flags().DurationVar(&respawnProtection, "respawn-protection", defaults.RespawnProtection, "How long ships cannot be damaged after spawning, or until they fire")
+		serverCmd.Flags().IntVar(&lives, "lives", 0, "Lives each player has before being eliminated, the last one left wins. 0 for unlimited lives")
+		serverCmd.Flags().StringVar(&leaderboardPath, "leaderboard", defaults.LeaderboardPath, "Where to save the all-time stats of the players, empty to keep them in memory")
+		serverCmd.Flags().StringVar(&spawnStrategy, "spawn", "farthest", "Where ships spawn, one of random, farthest or fixed")
+		serverCmd.Flags().BoolVar(&wrap, "wrap", false, "Wrap ships around the edges of the map instead of stopping them")
+		serverCmd.Flags().Float64Var(&mapWidth, "map-width", defaults.MapWidth, "The width of the map in pixels")
+		serverCmd.Flags().Float64Var(&mapHeight, "map-height", defaults.MapHeight, "The height of the map in pixels")
+		serverCmd.Flags().StringVar(&tlsCertFile, "tls-cert", "", "Path to the certificate to serve over HTTPS and WSS, needs --tls-key")
+		serverCmd.Flags().StringVar(&tlsKeyFile, "tls-key", "", "Path to the private key of the --tls-cert certificate")
+		serverCmd.Flags().DurationVar(&rapidFireCooldown, "rapid-fire-cooldown", defaults.RapidFireCooldown, "Minimum time between two shots while rapid fire is active")
 
 		rootCmd.AddCommand(serverCmd)
 	}
 
 	// Client command
 	{
+		defaults := config.DefaultClientConfig()
+
+		var configPath string
 		var port int
 		var address string
 		var secure bool
+		var serverUrl string
+		var insecure bool
+		var keyBindingsPath string
+		var volume float64
+		var debug bool
+		var aimAtCursor bool
+		var recordPath string
+		var replayPath string
+		var renderScale float64
+		var logLevel string
 		clientCmd := &cobra.Command{
 			Use:   "client",
 			Short: "Run the native client",
 			Run: func(cmd *cobra.Command, args []string) {
-				protocol := "ws"
-				if secure {
-					protocol = "wss"
+				if debug {
+					logLevel = "debug"
+				}
+				logger, err := newLogger(logLevel)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
 				}
 
-				url := fmt.Sprintf("%s://%s:%d/play/ws", protocol, address, port)
-				config := config.ClientConfig{
-					ScreenWidth:        1080,
-					ScreenHeight:       720,
-					ServerWebsocketURL: url,
+				// Flags given on the command line take precedence over the
+				// config file.
+				clientConfig := config.DefaultClientConfig()
+				clientConfig.Logger = logger
+				clientConfig.ReplayPath = replayPath
+				if configPath != "" {
+					if err := config.LoadClientConfig(configPath, &clientConfig); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
 				}
 
-				app := client.NewApp(&config)
+				if cmd.Flags().Changed("address") || cmd.Flags().Changed("port") || cmd.Flags().Changed("secure") {
+					protocol := "ws"
+					if secure {
+						protocol = "wss"
+					}
+					clientConfig.ServerWebsocketURL = fmt.Sprintf("%s://%s:%d/play/ws", protocol, address, port)
+				}
+				override(cmd, "server", &clientConfig.ServerWebsocketURL, serverUrl)
+				override(cmd, "insecure", &clientConfig.InsecureSkipVerify, insecure)
+				override(cmd, "volume", &clientConfig.Volume, volume)
+				override(cmd, "aim-at-cursor", &clientConfig.AimAtCursor, aimAtCursor)
+				override(cmd, "record", &clientConfig.RecordPath, recordPath)
+				override(cmd, "render-scale", &clientConfig.RenderScale, renderScale)
+
+				if cmd.Flags().Changed("keybindings") {
+					if clientConfig.KeyBindings, err = config.LoadKeyBindings(keyBindingsPath); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+
+				if err := clientConfig.Validate(); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				app := client.NewApp(&clientConfig)
 				if err := app.Run(); err != nil {
 					fmt.Println(err)
 					os.Exit(1)
@@ -96,9 +248,23 @@ func main() {
 			},
 		}
 
+		clientCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to a JSON file with the settings of the client, overridden by the flags given")
 		clientCmd.Flags().IntVarP(&port, "port", "p", 8080, "Port of the server")
 		clientCmd.Flags().StringVarP(&address, "address", "a", "localhost", "Address of the server")
 		clientCmd.Flags().BoolVarP(&secure, "secure", "s", false, "Whether to use WSS")
+		clientCmd.Flags().StringVar(&serverUrl, "server", "", "Websocket URL of the server, such as wss://example.com/play/ws, instead of --address, --port and --secure")
+		clientCmd.Flags().BoolVar(&insecure, "insecure", false, "Accept any certificate from a wss:// server, for development servers with self-signed certificates")
+		clientCmd.Flags().BoolVar(&aimAtCursor, "aim-at-cursor", false, "Fire towards the mouse cursor instead of straight ahead")
+		clientCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Log the network errors, same as --log-level debug")
+		clientCmd.Flags().StringVar(&logLevel, "log-level", "info", "The least severe messages logged, one of debug, info, warn or error")
+		clientCmd.Flags().StringVar(&recordPath, "record", "", "Record every match played to this file")
+		clientCmd.Flags().StringVar(&replayPath, "replay", "", "Play back a match recorded with --record instead of opening the menu")
+		clientCmd.Flags().Float64Var(&renderScale, "render-scale", defaults.RenderScale, "How much larger the arena is drawn, 0 to fit it to the height of the screen")
+		clientCmd.Flags().Float64VarP(&volume, "volume", "v", defaults.Volume, "Master volume, from 0 to 1")
+		clientCmd.Flags().StringVarP(&keyBindingsPath, "keybindings", "k", "", "Path to a JSON file with custom key bindings")
+		clientCmd.MarkFlagsMutuallyExclusive("server", "address")
+		clientCmd.MarkFlagsMutuallyExclusive("server", "port")
+		clientCmd.MarkFlagsMutuallyExclusive("server", "secure")
 
 		rootCmd.AddCommand(clientCmd)
 	}
@@ -109,3 +275,19 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// Sets the value to the one of the flag, if the flag was given.
+func override[T any](cmd *cobra.Command, flag string, value *T, flagValue T) {
+	if cmd.Flags().Changed(flag) {
+		*value = flagValue
+	}
+}
+
+// Logs to the standard error the messages at least as severe as the level.
+func newLogger(level string) (*slog.Logger, error) {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("Invalid log level %q", level)
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: parsed})), nil
+}
@@ -0,0 +1,38 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"testing"
+
+	"github.com/yohamta/donburi"
+)
+
+func TestBulletNeverHitsItsShooter(t *testing.T) {
+	for _, mode := range []types.WorldMode{types.WorldClamp, types.WorldWrap} {
+		simulation := NewGameSimulation()
+		simulation.WorldMode = mode
+
+		hits := map[types.PlayerId]int{}
+		simulation.OnBulletCollide = func(player *donburi.Entry, bullet *donburi.Entry) {
+			hits[component.Player.Get(player).Id]++
+		}
+
+		// The bullet starts right on top of the shooter and flies down into
+		// the target.
+		shooter := newTestPlayer(simulation, 0, 1000, 1000)
+		newTestPlayer(simulation, 1, 1000, 1100)
+
+		simulation.FireBullet(shooter, component.Position.GetValue(shooter))
+		for range 60 {
+			simulation.Update(1.0 / 60)
+		}
+
+		if hits[0] != 0 {
+			t.Errorf("The shooter was hit by their own bullet %d times in %s", hits[0], mode)
+		}
+		if hits[1] != 1 {
+			t.Errorf("The target was hit %d times in %s, expected once", hits[1], mode)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package component
+
+import (
+	"astro-blasters/game/types"
+
+	"github.com/yohamta/donburi"
+)
+
+type AsteroidData struct {
+	Id     types.AsteroidId
+	Radius float64
+}
+
+var Asteroid = donburi.NewComponentType[AsteroidData]()
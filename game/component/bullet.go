@@ -2,12 +2,49 @@ package component
 
 import (
 	"astro-blasters/game/types"
+	"math"
 
 	"github.com/yohamta/donburi"
 )
 
 type BulletData struct {
 	FiredBy types.PlayerId
+	Team    types.Team
+	Speed   float64
+	Damage  float64
+
+	// Missiles turn towards their target until it dies, the target is
+	// `types.InvalidPlayerId` for bullets that fly straight.
+	MissileId types.MissileId
+	TargetId  types.PlayerId
+	TurnRate  float64
+
+	// How far the bullet flew so far, which weakens it.
+	Distance float64
+	Falloff  DamageFalloff
+}
+
+// Bullets deal their full damage up to `Start` pixels, less and less up to
+// `End` and `MinFactor` of it past that. Bullets with `End` at or before
+// `Start` deal their full damage at any distance.
+type DamageFalloff struct {
+	Start     float64
+	End       float64
+	MinFactor float64
+}
+
+// Returns how much of its damage a bullet that flew the distance deals.
+func (self DamageFalloff) Factor(distance float64) float64 {
+	if self.End <= self.Start {
+		return 1
+	}
+	t := math.Max(0, math.Min(1, (distance-self.Start)/(self.End-self.Start)))
+	return 1 - t*(1-self.MinFactor)
+}
+
+// Returns the damage the bullet deals to what it hits now.
+func (self *BulletData) DamageDealt() float64 {
+	return self.Damage * self.Falloff.Factor(self.Distance)
 }
 
 var Bullet = donburi.NewComponentType[BulletData]()
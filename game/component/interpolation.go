@@ -0,0 +1,50 @@
+package component
+
+import (
+	"time"
+
+	"github.com/yohamta/donburi"
+)
+
+// Tracks how far the rendered position of an entity is from its simulated
+// position. Whenever the server corrects the simulated position the offset
+// absorbs the jump, and then decays so the sprite glides to the target.
+type InterpolationData struct {
+	OffsetX     float64
+	OffsetY     float64
+	OffsetAngle float64
+
+	// When the server last told us where the entity is.
+	LastRetargeted time.Time
+}
+
+// Moves the simulated position to the target while keeping the rendered
+// position where it currently is.
+func (self *InterpolationData) Retarget(current *PositionData, target PositionData) {
+	self.OffsetX += current.X - target.X
+	self.OffsetY += current.Y - target.Y
+
+	// Offsets piling up over several corrections could otherwise add up to
+	// more than half a turn, which would spin the ship the long way.
+	self.OffsetAngle = AngleDifference(current.Angle+self.OffsetAngle, target.Angle)
+	self.LastRetargeted = time.Now()
+	*current = target
+}
+
+// Shrinks the offset by the given factor, where 1 snaps immediately to the
+// simulated position.
+func (self *InterpolationData) Update(factor float64) {
+	self.OffsetX *= 1 - factor
+	self.OffsetY *= 1 - factor
+	self.OffsetAngle = LerpAngle(self.OffsetAngle, 0, factor)
+}
+
+// Returns the position the entity should be drawn at.
+func (self *InterpolationData) Rendered(position PositionData) PositionData {
+	position.X += self.OffsetX
+	position.Y += self.OffsetY
+	position.Angle += self.OffsetAngle
+	return position
+}
+
+var Interpolation = donburi.NewComponentType[InterpolationData]()
@@ -0,0 +1,26 @@
+package component
+
+import (
+	"github.com/yohamta/donburi"
+)
+
+// A short lived speck used for visual effects only, it never takes part in
+// the simulation.
+type ParticleData struct {
+	X         float64
+	Y         float64
+	VelocityX float64
+	VelocityY float64
+
+	// Both are counted in frames.
+	Age      int
+	Lifetime int
+}
+
+// How far the particle is through its life, from 0 when spawned to 1 when it
+// should be removed.
+func (self *ParticleData) Progress() float64 {
+	return float64(self.Age) / float64(self.Lifetime)
+}
+
+var Particle = donburi.NewComponentType[ParticleData]()
@@ -2,6 +2,7 @@ package component
 
 import (
 	"astro-blasters/game/types"
+	"time"
 
 	"github.com/yohamta/donburi"
 )
@@ -9,16 +10,112 @@ import (
 type PlayerData struct {
 	Name   string
 	Health float64
+	Shield float64
 	Score  int
 	Id     types.PlayerId
+	Team   types.Team
+	Class  types.ShipClass
+	Weapon types.WeaponId
 
 	IsAlive     bool
 	IsConnected bool
 
+	// Lives left in an elimination game, unused otherwise.
+	Lives int
+
 	IsRotatingClockwise        bool
 	IsRotatingCounterClockwise bool
 	IsMovingForward            bool
 	IsFiringBullet             bool
+
+	// Players steering with an analog stick turn towards the heading it
+	// points at, in radians, instead of holding a direction to rotate in.
+	IsSteering bool
+	SteerAngle float64
+
+	// When the player last fired, used to enforce the fire cooldown.
+	LastFired time.Time
+
+	// Players aiming at their cursor fire along the aim angle instead of
+	// the heading of their ship.
+	IsAiming bool
+	AimAngle float64
+
+	// When the player last took damage, the shield only regenerates after a
+	// while without any.
+	LastDamaged time.Time
+
+	// Until when the ship flashes red after being hit, only used by the
+	// clients.
+	HitFlashExpiresWhen time.Time
+
+	// When the ship last boosted and until when it may go past its top
+	// speed.
+	LastBoosted      time.Time
+	BoostExpiresWhen time.Time
+
+	// Ships that just spawned cannot be damaged until then, or until they
+	// fire.
+	InvulnerableUntil time.Time
+
+	// Effects granted by power-ups.
+	RapidFireExpiresWhen  time.Time
+	SpeedBoostExpiresWhen time.Time
+	ShotgunExpiresWhen    time.Time
+	MissileExpiresWhen    time.Time
+}
+
+// Takes the damage out of the shield first and the rest out of the health,
+// which never goes below zero.
+func (self *PlayerData) TakeDamage(damage float64) {
+	absorbed := min(self.Shield, damage)
+	self.Shield -= absorbed
+	self.Health = max(0, self.Health-(damage-absorbed))
+	self.LastDamaged = time.Now()
+}
+
+// Ends the effects of every power-up the player collected.
+func (self *PlayerData) ClearPowerUps() {
+	self.RapidFireExpiresWhen = time.Time{}
+	self.SpeedBoostExpiresWhen = time.Time{}
+	self.ShotgunExpiresWhen = time.Time{}
+	self.MissileExpiresWhen = time.Time{}
+}
+
+func (self *PlayerData) HasRapidFire() bool {
+	return time.Now().Before(self.RapidFireExpiresWhen)
+}
+
+func (self *PlayerData) IsInvulnerable() bool {
+	return time.Now().Before(self.InvulnerableUntil)
+}
+
+func (self *PlayerData) IsBoosting() bool {
+	return time.Now().Before(self.BoostExpiresWhen)
+}
+
+func (self *PlayerData) HasSpeedBoost() bool {
+	return time.Now().Before(self.SpeedBoostExpiresWhen)
+}
+
+func (self *PlayerData) HasShotgun() bool {
+	return time.Now().Before(self.ShotgunExpiresWhen)
+}
+
+func (self *PlayerData) HasMissile() bool {
+	return time.Now().Before(self.MissileExpiresWhen)
+}
+
+// Returns the weapon the player fires right now, power-ups take over the
+// player's own weapon while they last.
+func (self *PlayerData) EquippedWeapon() types.WeaponId {
+	if self.HasShotgun() {
+		return types.WeaponShotgun
+	}
+	if self.HasMissile() {
+		return types.WeaponMissile
+	}
+	return self.Weapon
 }
 
 var Player = donburi.NewComponentType[PlayerData]()
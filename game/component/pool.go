@@ -0,0 +1,57 @@
+package component
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/component"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Marks the entities sitting in a pool, they are skipped by `Active` queries.
+var Pooled = donburi.NewTag()
+
+// Keeps the entities of short lived objects such as bullets and particles
+// around once they are done, so that they can be handed out again instead of
+// creating new ones every frame.
+type Pool struct {
+	world      donburi.World
+	components []component.IComponentType
+	free       []donburi.Entity
+}
+
+func NewPool(world donburi.World, components ...component.IComponentType) *Pool {
+	return &Pool{world: world, components: components}
+}
+
+// Returns an entity with the components of the pool. Reused entities keep
+// their old data, callers are expected to set every component.
+func (self *Pool) Get() *donburi.Entry {
+	for len(self.free) > 0 {
+		entity := self.free[len(self.free)-1]
+		self.free = self.free[:len(self.free)-1]
+
+		// The entity went away with the world it belonged to.
+		if !self.world.Valid(entity) {
+			continue
+		}
+
+		entry := self.world.Entry(entity)
+		entry.RemoveComponent(Pooled)
+		return entry
+	}
+
+	return self.world.Entry(self.world.Create(self.components...))
+}
+
+// Hands the entity back to the pool.
+func (self *Pool) Put(entry *donburi.Entry) {
+	if entry.HasComponent(Pooled) {
+		return
+	}
+	entry.AddComponent(Pooled)
+	self.free = append(self.free, entry.Entity())
+}
+
+// Matches the entities with the given components that aren't in a pool.
+func Active(components ...component.IComponentType) filter.LayoutFilter {
+	return filter.And(filter.Contains(components...), filter.Not(filter.Contains(Pooled)))
+}
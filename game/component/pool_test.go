@@ -0,0 +1,28 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/yohamta/donburi"
+)
+
+// Compares handing out pooled bullets with creating and removing them.
+func BenchmarkPool(b *testing.B) {
+	b.Run("Pooled", func(b *testing.B) {
+		world := donburi.NewWorld()
+		pool := NewPool(world, Bullet, Position)
+
+		b.ReportAllocs()
+		for range b.N {
+			pool.Put(pool.Get())
+		}
+	})
+	b.Run("Fresh", func(b *testing.B) {
+		world := donburi.NewWorld()
+
+		b.ReportAllocs()
+		for range b.N {
+			world.Remove(world.Create(Bullet, Position))
+		}
+	})
+}
@@ -9,6 +9,9 @@ type PositionData struct {
 	X     float64
 	Y     float64
 	Angle float64
+
+	VelocityX float64
+	VelocityY float64
 }
 
 var Position = donburi.NewComponentType[PositionData]()
@@ -22,6 +25,77 @@ func (self *PositionData) Forward(magnitude float64) {
 	self.X += magnitude * math.Sin(self.Angle)
 }
 
+// Rotates by the given amount of degrees, keeping the angle within [0, 2π).
 func (self *PositionData) Rotate(magnitude float64) {
-	self.Angle += magnitude * math.Pi / 180
+	self.Angle = WrapAngle(self.Angle + magnitude*math.Pi/180)
+}
+
+// Turns towards the target angle in radians through the shortest direction,
+// by at most `maxStep` radians.
+func (self *PositionData) RotateToward(targetAngle float64, maxStep float64) {
+	difference := AngleDifference(targetAngle, self.Angle)
+	if math.Abs(difference) <= maxStep {
+		self.Angle = WrapAngle(targetAngle)
+		return
+	}
+	self.Angle = WrapAngle(self.Angle + math.Copysign(maxStep, difference))
+}
+
+// Wraps the angle in radians into [0, 2π).
+func WrapAngle(angle float64) float64 {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	// Tiny negative angles round up to 2π, which is the same as 0.
+	if angle >= 2*math.Pi {
+		angle = 0
+	}
+	return angle
+}
+
+// Returns the shortest signed rotation from `b` to `a`, within [-π, π).
+func AngleDifference(a, b float64) float64 {
+	return WrapAngle(a-b+math.Pi) - math.Pi
+}
+
+// Interpolates from `a` to `b` the short way around, so that going from just
+// below 2π to just above 0 doesn't spin all the way back.
+func LerpAngle(a, b, t float64) float64 {
+	return a + AngleDifference(b, a)*t
+}
+
+// Accelerates along the current angle, the velocity is only integrated into
+// the position once `ApplyPhysics` is called.
+func (self *PositionData) Thrust(magnitude float64) {
+	self.VelocityY -= magnitude * math.Cos(self.Angle)
+	self.VelocityX += magnitude * math.Sin(self.Angle)
+}
+
+// Returns the magnitude of the velocity.
+func (self *PositionData) Speed() float64 {
+	return math.Hypot(self.VelocityX, self.VelocityY)
+}
+
+// Scales the velocity down to `maxSpeed` if it is any faster, keeping its
+// direction.
+func (self *PositionData) LimitSpeed(maxSpeed float64) {
+	speed := self.Speed()
+	if speed <= maxSpeed {
+		return
+	}
+	self.VelocityX *= maxSpeed / speed
+	self.VelocityY *= maxSpeed / speed
+}
+
+// Moves the position by its velocity over `dt` seconds and slows it down by
+// the given drag, the rate per second at which the velocity decays. A drag of
+// 0 lets it coast forever.
+func (self *PositionData) ApplyPhysics(drag float64, dt float64) {
+	self.X += self.VelocityX * dt
+	self.Y += self.VelocityY * dt
+
+	decay := math.Exp(-drag * dt)
+	self.VelocityX *= decay
+	self.VelocityY *= decay
 }
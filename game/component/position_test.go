@@ -0,0 +1,223 @@
+package component
+
+import (
+	"math"
+	"testing"
+)
+
+// Close enough for angles and positions after a few floating point steps.
+const epsilon = 1e-9
+
+func TestForward(t *testing.T) {
+	tests := []struct {
+		name     string
+		angle    float64
+		expected PositionData
+	}{
+		{name: "up", angle: 0, expected: PositionData{X: 0, Y: -10}},
+		{name: "right", angle: math.Pi / 2, expected: PositionData{X: 10, Y: 0}},
+		{name: "down", angle: math.Pi, expected: PositionData{X: 0, Y: 10}},
+		{name: "left", angle: 3 * math.Pi / 2, expected: PositionData{X: -10, Y: 0}},
+	}
+
+	for _, test := range tests {
+		position := PositionData{Angle: test.angle}
+		position.Forward(10)
+		if math.Abs(position.X-test.expected.X) > epsilon || math.Abs(position.Y-test.expected.Y) > epsilon {
+			t.Errorf("Moving %s ended at (%g, %g), expected (%g, %g)", test.name, position.X, position.Y, test.expected.X, test.expected.Y)
+		}
+	}
+}
+
+func TestWrapAngle(t *testing.T) {
+	tests := []struct {
+		name     string
+		angle    float64
+		expected float64
+	}{
+		{name: "zero", angle: 0, expected: 0},
+		{name: "within range", angle: 1, expected: 1},
+		{name: "below zero", angle: -math.Pi / 2, expected: 3 * math.Pi / 2},
+		{name: "far below zero", angle: -5 * math.Pi, expected: math.Pi},
+		{name: "tiny negative", angle: -1e-18, expected: 0},
+		{name: "at 2π", angle: 2 * math.Pi, expected: 0},
+		{name: "above 2π", angle: 2*math.Pi + 1, expected: 1},
+		{name: "far above 2π", angle: 1000*math.Pi + math.Pi/2, expected: math.Pi / 2},
+	}
+
+	for _, test := range tests {
+		angle := WrapAngle(test.angle)
+		if angle < 0 || angle >= 2*math.Pi {
+			t.Errorf("Wrapping %s gave %g, outside of [0, 2π)", test.name, angle)
+		}
+		if math.Abs(angle-test.expected) > epsilon {
+			t.Errorf("Wrapping %s gave %g, expected %g", test.name, angle, test.expected)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		name     string
+		angle    float64
+		degrees  float64
+		expected float64
+	}{
+		{name: "clockwise", angle: 0, degrees: 90, expected: math.Pi / 2},
+		{name: "counter clockwise past 0", angle: 0, degrees: -90, expected: 3 * math.Pi / 2},
+		{name: "clockwise past 2π", angle: 3 * math.Pi / 2, degrees: 180, expected: math.Pi / 2},
+		{name: "full turn", angle: 1, degrees: 360, expected: 1},
+		{name: "many turns", angle: 1, degrees: 3600, expected: 1},
+	}
+
+	for _, test := range tests {
+		position := PositionData{Angle: test.angle}
+		position.Rotate(test.degrees)
+		if math.Abs(position.Angle-test.expected) > epsilon {
+			t.Errorf("Rotating %s gave %g, expected %g", test.name, position.Angle, test.expected)
+		}
+	}
+}
+
+// A ship flying a square ends up where it started, facing the way it
+// started.
+func TestMovementSequence(t *testing.T) {
+	position := PositionData{X: 100, Y: 100}
+	expected := []PositionData{
+		{X: 100, Y: 90, Angle: math.Pi / 2},
+		{X: 110, Y: 90, Angle: math.Pi},
+		{X: 110, Y: 100, Angle: 3 * math.Pi / 2},
+		{X: 100, Y: 100, Angle: 0},
+	}
+
+	for i, step := range expected {
+		position.Forward(10)
+		position.Rotate(90)
+		if math.Abs(position.X-step.X) > epsilon || math.Abs(position.Y-step.Y) > epsilon || math.Abs(AngleDifference(position.Angle, step.Angle)) > epsilon {
+			t.Errorf("After side %d the ship is at (%g, %g) facing %g, expected (%g, %g) facing %g", i+1, position.X, position.Y, position.Angle, step.X, step.Y, step.Angle)
+		}
+	}
+}
+
+func TestRotateToward(t *testing.T) {
+	tests := []struct {
+		name     string
+		angle    float64
+		target   float64
+		maxStep  float64
+		expected float64
+	}{
+		{name: "clockwise", angle: 0, target: 1, maxStep: 0.25, expected: 0.25},
+		{name: "counter clockwise", angle: 1, target: 0, maxStep: 0.25, expected: 0.75},
+		{name: "clockwise across 2π", angle: 2*math.Pi - 0.1, target: 0.3, maxStep: 0.25, expected: 0.15},
+		{name: "counter clockwise across 0", angle: 0.1, target: 2*math.Pi - 0.3, maxStep: 0.25, expected: 2*math.Pi - 0.15},
+		{name: "reaching the target", angle: 0, target: 0.1, maxStep: 0.25, expected: 0.1},
+		{name: "reaching the target across 0", angle: 2*math.Pi - 0.05, target: 0.05, maxStep: 0.25, expected: 0.05},
+		{name: "unwrapped target", angle: 0, target: 2*math.Pi + 0.1, maxStep: 0.25, expected: 0.1},
+	}
+
+	for _, test := range tests {
+		position := PositionData{Angle: test.angle}
+		position.RotateToward(test.target, test.maxStep)
+		if math.Abs(position.Angle-test.expected) > epsilon {
+			t.Errorf("Turning %s gave %g, expected %g", test.name, position.Angle, test.expected)
+		}
+	}
+}
+
+func TestRotateTowardNeverOvershoots(t *testing.T) {
+	for _, target := range []float64{0, 0.5, math.Pi, 4, 2*math.Pi - 0.01} {
+		position := PositionData{Angle: 1}
+		previous := math.Abs(AngleDifference(target, position.Angle))
+
+		// Once on target every further step has to stay there.
+		for range 100 {
+			position.RotateToward(target, 0.3)
+			remaining := math.Abs(AngleDifference(target, position.Angle))
+			if remaining > previous+epsilon {
+				t.Fatalf("Turning to %g went from %g to %g away from it", target, previous, remaining)
+			}
+			previous = remaining
+		}
+
+		if previous > epsilon {
+			t.Errorf("Turning to %g stopped %g away from it", target, previous)
+		}
+	}
+}
+
+func TestThrustNeverExceedsSpeedLimit(t *testing.T) {
+	const maxSpeed = 300
+
+	tests := []struct {
+		name   string
+		thrust float64
+		turn   float64
+	}{
+		{name: "straight", thrust: 50, turn: 0},
+		{name: "turning", thrust: 50, turn: 7},
+		{name: "a huge impulse", thrust: 10 * maxSpeed, turn: 45},
+		{name: "against the velocity", thrust: 80, turn: 180},
+	}
+
+	for _, test := range tests {
+		position := PositionData{}
+		for range 1000 {
+			position.Thrust(test.thrust)
+			position.ApplyPhysics(0.5, 1.0/60)
+			position.LimitSpeed(maxSpeed)
+			position.Rotate(test.turn)
+
+			if speed := position.Speed(); speed > maxSpeed+epsilon {
+				t.Fatalf("Thrusting %s reached %g, past the limit of %d", test.name, speed, maxSpeed)
+			}
+		}
+	}
+}
+
+func TestLimitSpeedKeepsDirection(t *testing.T) {
+	position := PositionData{VelocityX: 300, VelocityY: -400}
+	position.LimitSpeed(100)
+
+	if math.Abs(position.Speed()-100) > epsilon {
+		t.Errorf("Limited the speed to %g, expected 100", position.Speed())
+	}
+	if math.Abs(position.VelocityX-60) > epsilon || math.Abs(position.VelocityY+80) > epsilon {
+		t.Errorf("Limiting the speed changed the direction to (%g, %g)", position.VelocityX, position.VelocityY)
+	}
+}
+
+func TestLerpAngleTakesTheShortWay(t *testing.T) {
+	degrees := math.Pi / 180
+
+	tests := []struct {
+		name     string
+		from, to float64
+		t        float64
+		expected float64
+	}{
+		{name: "350° to 10° halfway", from: 350 * degrees, to: 10 * degrees, t: 0.5, expected: 0},
+		{name: "350° to 10° a quarter", from: 350 * degrees, to: 10 * degrees, t: 0.25, expected: 355 * degrees},
+		{name: "10° to 350° halfway", from: 10 * degrees, to: 350 * degrees, t: 0.5, expected: 0},
+		{name: "without crossing 0", from: 10 * degrees, to: 50 * degrees, t: 0.5, expected: 30 * degrees},
+		{name: "at the start", from: 350 * degrees, to: 10 * degrees, t: 0, expected: 350 * degrees},
+		{name: "at the end", from: 350 * degrees, to: 10 * degrees, t: 1, expected: 10 * degrees},
+	}
+
+	for _, test := range tests {
+		// The result may be past 2π or below 0, it only has to point the
+		// right way.
+		angle := LerpAngle(test.from, test.to, test.t)
+		if math.Abs(AngleDifference(angle, test.expected)) > epsilon {
+			t.Errorf("Interpolating %s gave %g°, expected %g°", test.name, angle/degrees, test.expected/degrees)
+		}
+	}
+
+	// Every step from 350° to 10° stays within the 20° between them.
+	for step := range 11 {
+		angle := LerpAngle(350*degrees, 10*degrees, float64(step)/10)
+		if math.Abs(AngleDifference(angle, 0)) > 10*degrees+epsilon {
+			t.Errorf("Interpolating from 350° to 10° went through %g°", WrapAngle(angle)/degrees)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package component
+
+import (
+	"astro-blasters/game/types"
+
+	"github.com/yohamta/donburi"
+)
+
+type PowerUpData struct {
+	Id   types.PowerUpId
+	Kind types.PowerUpKind
+}
+
+var PowerUp = donburi.NewComponentType[PowerUpData]()
@@ -4,7 +4,6 @@ import (
 	"astro-blasters/assets"
 	"astro-blasters/game/component"
 	"astro-blasters/game/types"
-	"log"
 	"math"
 	"math/rand"
 	"time"
@@ -15,60 +14,182 @@ import (
 	"github.com/yohamta/donburi/filter"
 )
 
+// Speeds are given per second, the simulation is advanced by the time that
+// passed since the last update.
 const (
 	PlayerDamagePerHit  = 5
-	PlayerMovementSpeed = 5
-	PlayerRotationSpeed = 5
-
-	BulletSpeed = 20
-
-	MapWidth  = 4096
-	MapHeight = 4096
+	PlayerAcceleration  = 900 // px/s²
+	PlayerDrag          = 3   // 1/s
+	PlayerRotationSpeed = 300 // °/s
+	PlayerMaxSpeed      = 360 // px/s
+
+	// Thrust weakens over the last part of a ship's speed range, so ships
+	// pick up speed quickly and ease into their top speed.
+	ThrustTaper = 0.2
+
+	BulletSpeed    = 1200 // px/s
+	BulletLifetime = time.Second
+
+	// Longer updates are cut short so that a hiccup doesn't teleport ships
+	// through each other.
+	MaxDeltaTime = 0.1
+
+	AsteroidDamage        = 20
+	AsteroidMinRadius     = 16
+	AsteroidMaxRadius     = 48
+	AsteroidMaxSpeed      = 120 // px/s
+	AsteroidRotationSpeed = 60  // °/s
+
+	MaxShield        = 30
+	ShieldRegenRate  = 5 // per second
+	ShieldRegenDelay = 3 * time.Second
+
+	// How long a dead ship waits before the server brings it back.
+	RespawnDelay = 3 * time.Second
+
+	PowerUpDuration      = 10 * time.Second
+	PowerUpRadius        = 32
+	SpeedBoostMultiplier = 1.5
+
+	// A boost adds this much speed along the heading at once and lets the ship
+	// go faster than its top speed for a moment.
+	BoostImpulse         = 700 // px/s
+	BoostDuration        = 400 * time.Millisecond
+	BoostCooldown        = 3 * time.Second
+	BoostSpeedMultiplier = 2.5
+
+	// The size of the map unless the server picks another one.
+	DefaultMapWidth  = 4096
+	DefaultMapHeight = 4096
 
 	ShipWidth  = 32
 	ShipHeight = 32
 )
 
 type GameSimulation struct {
-	ECS             *ecs.ECS
-	OnBulletCollide func(player *donburi.Entry, bullet *donburi.Entry)
-	OnBulletFire    func(player *donburi.Entry)
+	ECS       *ecs.ECS
+	WorldMode types.WorldMode
+
+	// The size of the map in pixels, clients play on the one the server
+	// sends them.
+	MapWidth  float64
+	MapHeight float64
+
+	OnBulletCollide   func(player *donburi.Entry, bullet *donburi.Entry)
+	OnBulletFire      func(player *donburi.Entry)
+	OnAsteroidCollide func(player *donburi.Entry, asteroid *donburi.Entry)
+	OnPowerUpCollect  func(player *donburi.Entry, powerUp *donburi.Entry)
+
+	// Bullets are fired too often to create a new entity for each of them.
+	bullets *component.Pool
+
+	// The ships that can be hit this tick, rebuilt at the start of every
+	// update.
+	targets *spatialGrid
+
+	// Returns where the ship was when the shooter fired, so that hits can be
+	// resolved against what the shooter saw. Nil uses the current positions.
+	RewindPlayer func(player *donburi.Entry, shooter types.PlayerId) *component.PositionData
+
+	// How much further than the current positions rewound ships can be.
+	RewindDistance float64
+
+	// The entities to remove once the systems are done iterating, removing
+	// them in the middle of a query can skip or repeat entities.
+	removals map[donburi.Entity]*donburi.Entry
+
+	// The stats of every weapon, clients are sent the ones the server uses.
+	Weapons map[types.WeaponId]WeaponStats
+
+	// What runs on every update, in order.
+	systems systems
 }
 
 func NewGameSimulation() *GameSimulation {
-	return &GameSimulation{
-		ECS:             ecs.NewECS(donburi.NewWorld()),
-		OnBulletCollide: func(player *donburi.Entry, bullet *donburi.Entry) {},
-		OnBulletFire:    func(player *donburi.Entry) {},
+	world := donburi.NewWorld()
+	simulation := &GameSimulation{
+		ECS:               ecs.NewECS(world),
+		MapWidth:          DefaultMapWidth,
+		MapHeight:         DefaultMapHeight,
+		targets:           newSpatialGrid(),
+		removals:          map[donburi.Entity]*donburi.Entry{},
+		Weapons:           DefaultWeapons(),
+		bullets:           component.NewPool(world, component.Bullet, component.Sprite, component.Position, component.Expirable),
+		OnBulletCollide:   func(player *donburi.Entry, bullet *donburi.Entry) {},
+		OnBulletFire:      func(player *donburi.Entry) {},
+		OnAsteroidCollide: func(player *donburi.Entry, asteroid *donburi.Entry) {},
+		OnPowerUpCollect:  func(player *donburi.Entry, powerUp *donburi.Entry) {},
 	}
+
+	simulation.systems.add(stageExpiry, simulation.expireEntities)
+	simulation.systems.add(stageTargets, simulation.updateTargets)
+	simulation.systems.add(stageCollision, simulation.updateBullets)
+	simulation.systems.add(stageCollision, simulation.updateAsteroids)
+	simulation.systems.add(stageCollision, simulation.updatePowerUps)
+	simulation.systems.add(stageMovement, simulation.updatePlayers)
+	simulation.systems.add(stageCleanup, simulation.updateRemovals)
+	return simulation
 }
 
-func (self *GameSimulation) Update() {
-	for expirable := range donburi.NewQuery(filter.Contains(component.Expirable)).Iter(self.ECS.World) {
+// Advances the simulation by `dt` seconds, running every system in the order
+// of its stage.
+func (self *GameSimulation) Update(dt float64) {
+	dt = math.Min(dt, MaxDeltaTime)
+	self.systems.update(dt)
+}
+
+// Queues the bullets, explosions and other short lived entities that ran
+// out of time for removal.
+func (self *GameSimulation) expireEntities(dt float64) {
+	for expirable := range donburi.NewQuery(component.Active(component.Expirable)).Iter(self.ECS.World) {
 		expirableData := component.Expirable.GetValue(expirable)
 		if time.Now().After(expirableData.ExpiresWhen) {
-			self.ECS.World.Remove(expirable.Entity())
+			self.QueueRemoval(expirable)
 		}
 	}
+}
 
-	for bullet := range donburi.NewQuery(filter.Contains(component.Bullet)).Iter(self.ECS.World) {
-		futureBulletPosition := component.Position.GetValue(bullet)
-		futureBulletPosition.Forward(-BulletSpeed)
+// Rebuilds the grid of the ships that can be hit this tick.
+func (self *GameSimulation) updateTargets(dt float64) {
+	self.targets.clear()
+	self.targets.resize(self.MapWidth, self.MapHeight, self.WorldMode == types.WorldWrap)
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.ECS.World) {
+		playerData := component.Player.Get(player)
+		if playerData.IsAlive && playerData.IsConnected {
+			self.targets.insert(player, component.Position.Get(player))
+		}
+	}
+}
 
-		didCollide := false
-		var collidedPlayer *donburi.Entry
+// Moves the bullets, steering the missiles, and resolves their hits.
+func (self *GameSimulation) updateBullets(dt float64) {
+	for bullet := range donburi.NewQuery(component.Active(component.Bullet)).Iter(self.ECS.World) {
+		// The bullet expired this tick.
+		if self.IsQueuedForRemoval(bullet) {
+			continue
+		}
 
-		for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.ECS.World) {
-			playerData := component.Player.Get(player)
-			isDamageable := playerData.IsAlive && playerData.IsConnected
+		bulletData := component.Bullet.Get(bullet)
+		futureBulletPosition := component.Position.GetValue(bullet)
+		self.steerMissile(bulletData, &futureBulletPosition, dt)
+		futureBulletPosition.Forward(-bulletData.Speed * dt)
+		bulletData.Distance += bulletData.Speed * dt
 
-			if isDamageable && component.Position.Get(player).IntersectsWith(&futureBulletPosition, 20) {
-				didCollide = true
-				collidedPlayer = player
+		collidedPlayer := self.targets.find(&futureBulletPosition, 20+self.RewindDistance, func(player *donburi.Entry) bool {
+			playerData := component.Player.Get(player)
+			return playerData.IsAlive && !isOwnBullet(bulletData, playerData) && !isFriendlyFire(bulletData, playerData) &&
+				self.intersects(self.hitPosition(player, bulletData.FiredBy), &futureBulletPosition, 20)
+		})
+
+		if collidedPlayer == nil {
+			if self.WorldMode == types.WorldWrap {
+				self.wrapToMap(&futureBulletPosition)
+			} else if !self.isInsideMap(&futureBulletPosition) {
+				// Nothing can be hit outside of the map.
+				self.QueueRemoval(bullet)
+				continue
 			}
-		}
 
-		if !didCollide {
 			component.Position.SetValue(bullet, futureBulletPosition)
 			continue
 		}
@@ -78,44 +199,145 @@ func (self *GameSimulation) Update() {
 		}
 
 		self.spawnExplosion(&futureBulletPosition)
-		self.ECS.World.Remove(bullet.Entity())
+		self.QueueRemoval(bullet)
 	}
+}
+
+// Drifts the asteroids and shatters the ones that ran into a ship.
+func (self *GameSimulation) updateAsteroids(dt float64) {
+	for asteroid := range donburi.NewQuery(filter.Contains(component.Asteroid)).Iter(self.ECS.World) {
+		asteroidData := component.Asteroid.Get(asteroid)
+		position := component.Position.Get(asteroid)
+
+		// Asteroids drift forever, wrapping around the edges of the map.
+		position.ApplyPhysics(0, dt)
+		position.Rotate(AsteroidRotationSpeed * dt)
+		self.wrapToMap(position)
+
+		radius := asteroidData.Radius + ShipWidth/2
+		collidedPlayer := self.targets.find(position, radius, func(player *donburi.Entry) bool {
+			// The ship may have been shot down earlier in this tick.
+			return component.Player.Get(player).IsAlive && self.intersects(component.Position.Get(player), position, radius)
+		})
+
+		if collidedPlayer == nil {
+			continue
+		}
+
+		// The asteroid shatters on impact.
+		if self.OnAsteroidCollide != nil {
+			self.OnAsteroidCollide(collidedPlayer, asteroid)
+		}
 
+		self.spawnExplosion(position)
+		self.QueueRemoval(asteroid)
+	}
+}
+
+// Hands the power-ups to the ships that flew over them.
+func (self *GameSimulation) updatePowerUps(dt float64) {
+	for powerUp := range donburi.NewQuery(filter.Contains(component.PowerUp)).Iter(self.ECS.World) {
+		position := component.Position.Get(powerUp)
+
+		player := self.targets.find(position, PowerUpRadius, func(player *donburi.Entry) bool {
+			return component.Player.Get(player).IsAlive && self.intersects(component.Position.Get(player), position, PowerUpRadius)
+		})
+
+		if player != nil {
+			if self.OnPowerUpCollect != nil {
+				self.OnPowerUpCollect(player, powerUp)
+			}
+			self.QueueRemoval(powerUp)
+		}
+	}
+}
+
+// Fires the guns, regenerates the shields and moves the ships.
+func (self *GameSimulation) updatePlayers(dt float64) {
 	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.ECS.World) {
 		playerData := component.Player.Get(player)
+		stats := GetShipStats(playerData.Class)
 
 		if playerData.IsFiringBullet {
 			self.OnBulletFire(player)
 		}
 
+		if playerData.IsAlive && time.Since(playerData.LastDamaged) > ShieldRegenDelay {
+			playerData.Shield = math.Min(playerData.Shield+ShieldRegenRate*dt, MaxShield)
+		}
+
+		acceleration := stats.Acceleration
+		maxSpeed := stats.MaxSpeed
+		if playerData.HasSpeedBoost() {
+			acceleration *= SpeedBoostMultiplier
+			maxSpeed *= SpeedBoostMultiplier
+		}
+		if playerData.IsBoosting() {
+			maxSpeed *= BoostSpeedMultiplier
+		}
+
 		futurePosition := component.Position.GetValue(player)
 		if playerData.IsMovingForward {
-			futurePosition.Forward(PlayerMovementSpeed)
+			futurePosition.Thrust(thrustFalloff(futurePosition.Speed(), maxSpeed) * acceleration * dt)
 		}
 
 		if playerData.IsRotatingClockwise {
-			futurePosition.Rotate(PlayerRotationSpeed)
+			futurePosition.Rotate(stats.RotationSpeed * dt)
 		}
 
 		if playerData.IsRotatingCounterClockwise {
-			futurePosition.Rotate(-PlayerRotationSpeed)
+			futurePosition.Rotate(-stats.RotationSpeed * dt)
 		}
 
-		if futurePosition.X < ShipWidth || futurePosition.X > MapWidth-ShipWidth {
-			continue
+		if playerData.IsSteering {
+			futurePosition.RotateToward(playerData.SteerAngle, stats.RotationSpeed*math.Pi/180*dt)
 		}
-		if futurePosition.Y < ShipHeight || futurePosition.Y > MapHeight-ShipHeight {
-			continue
+
+		// Ships coast even when no input is held.
+		futurePosition.ApplyPhysics(PlayerDrag, dt)
+		futurePosition.LimitSpeed(maxSpeed)
+		if self.WorldMode == types.WorldWrap {
+			self.wrapToMap(&futurePosition)
+		} else {
+			self.clampToMap(&futurePosition)
 		}
 
 		component.Position.SetValue(player, futurePosition)
 	}
 }
 
-func (self *GameSimulation) UpdatePlayerHealth(playerId types.PlayerId, health float64) {
+// Removes the entities the systems queued for removal.
+func (self *GameSimulation) updateRemovals(dt float64) {
+	self.flushRemovals()
+}
+
+// Returns how much of its thrust a ship going at the given speed still gets.
+func thrustFalloff(speed float64, maxSpeed float64) float64 {
+	return math.Max(0, math.Min(1, (maxSpeed-speed)/(maxSpeed*ThrustTaper)))
+}
+
+func (self *GameSimulation) UpdatePlayerShield(playerId types.PlayerId, shield float64) {
 	player := self.FindCorrespondingPlayer(playerId)
+	if player == nil {
+		return
+	}
 	playerData := component.Player.Get(player)
-	playerData.Health = health
+	playerData.Shield = shield
+	playerData.LastDamaged = time.Now()
+}
+
+func (self *GameSimulation) UpdatePlayerHealth(playerId types.PlayerId, health float64) {
+	player := self.FindCorrespondingPlayer(playerId)
+	if player == nil {
+		return
+	}
+	SetPlayerHealth(component.Player.Get(player), health)
+}
+
+// Sets the health of the player, kept between zero and the most their ship
+// can have.
+func SetPlayerHealth(playerData *component.PlayerData, health float64) {
+	playerData.Health = max(0, min(health, GetShipStats(playerData.Class).MaxHealth))
 }
 
 func (self *GameSimulation) RegisterPlayerDisconnection(player *donburi.Entry) {
@@ -123,9 +345,13 @@ func (self *GameSimulation) RegisterPlayerDisconnection(player *donburi.Entry) {
 	playerData.IsConnected = false
 }
 
+// Registers the death of the victim. The killer may be nil if they have
+// already left the game.
 func (self *GameSimulation) RegisterPlayerDeath(victim, killer *donburi.Entry) {
-	killerData := component.Player.Get(killer)
-	killerData.Score += 10
+	if killer != nil {
+		killerData := component.Player.Get(killer)
+		killerData.Score += 10
+	}
 
 	victimData := component.Player.Get(victim)
 	victimData.Score /= 2
@@ -133,13 +359,21 @@ func (self *GameSimulation) RegisterPlayerDeath(victim, killer *donburi.Entry) {
 	victimData.IsRotatingClockwise = false
 	victimData.IsMovingForward = false
 	victimData.IsRotatingCounterClockwise = false
+	victimData.IsSteering = false
 	victimData.IsAlive = false
+
+	victimData.Health = 0
+	victimData.Shield = 0
+	victimData.ClearPowerUps()
+
+	self.spawnExplosion(component.Position.Get(victim))
 }
 
 func (self *GameSimulation) RegisterPlayerMove(playerId types.PlayerId, move types.PlayerMove) {
+	// Moves can arrive for players we haven't heard of yet.
 	player := self.FindCorrespondingPlayer(playerId)
 	if player == nil {
-		log.Fatal("Invalid player Id")
+		return
 	}
 
 	playerData := component.Player.Get(player)
@@ -163,38 +397,113 @@ func (self *GameSimulation) RegisterPlayerMove(playerId types.PlayerId, move typ
 		playerData.IsRotatingCounterClockwise = true
 	case types.PlayerStopRotateCounterClockwise:
 		playerData.IsRotatingCounterClockwise = false
+
+	case types.PlayerBoost:
+		self.boost(player)
+	}
+}
+
+// Turns the ship of the player towards the heading at their ship's turn rate
+// while they steer, or stops turning it.
+func (self *GameSimulation) RegisterPlayerSteering(playerId types.PlayerId, isSteering bool, angle float64) {
+	player := self.FindCorrespondingPlayer(playerId)
+	if player == nil {
+		return
 	}
+
+	playerData := component.Player.Get(player)
+	playerData.IsSteering = isSteering
+	playerData.SteerAngle = angle
+}
+
+// Pushes the ship along its heading, unless it boosted too recently.
+func (self *GameSimulation) boost(player *donburi.Entry) {
+	playerData := component.Player.Get(player)
+	if !playerData.IsAlive || BoostCooldownRemaining(playerData) > 0 {
+		return
+	}
+
+	now := time.Now()
+	playerData.LastBoosted = now
+	playerData.BoostExpiresWhen = now.Add(BoostDuration)
+	component.Position.Get(player).Thrust(BoostImpulse)
+}
+
+// Returns how long until the player can boost again.
+func BoostCooldownRemaining(playerData *component.PlayerData) time.Duration {
+	return max(BoostCooldown-time.Since(playerData.LastBoosted), 0)
+}
+
+// Returns the angle the bullets of the player leave at.
+func GetFireAngle(player *donburi.Entry) float64 {
+	playerData := component.Player.Get(player)
+	if playerData.IsAiming {
+		return playerData.AimAngle
+	}
+	return component.Position.Get(player).Angle
 }
 
 func (self *GameSimulation) RegisterPlayerFire(player *donburi.Entry) {
-	playerPosition := component.Position.Get(player)
+	self.RegisterPlayerFireAt(player, GetFireAngle(player))
+}
+
+// Fires the equipped weapon along the given angle without locking on to
+// anyone.
+func (self *GameSimulation) RegisterPlayerFireAt(player *donburi.Entry, angle float64) {
+	self.FireShot(player, Shot{Angle: angle, TargetId: types.InvalidPlayerId})
+}
 
-	bullet1 := *playerPosition
-	bullet1.Angle += math.Pi
-	bullet1.X -= 15 * math.Cos(bullet1.Angle)
-	bullet1.Y -= 15 * math.Sin(bullet1.Angle)
-	bullet1.Forward(-40)
+// Fires the pellets of the equipped weapon from the ship along the angle of
+// the shot, spread across the width of the ship and fanned out by the
+// weapon.
+func (self *GameSimulation) FireShot(player *donburi.Entry, shot Shot) {
+	// Shooting gives up the protection of a fresh spawn.
+	component.Player.Get(player).InvulnerableUntil = time.Time{}
 
-	bullet2 := *playerPosition
-	bullet2.Angle += math.Pi
-	bullet2.X += 15 * math.Cos(bullet2.Angle)
-	bullet2.Y += 15 * math.Sin(bullet2.Angle)
-	bullet2.Forward(-40)
+	angle := shot.Angle
+	playerPosition := component.Position.Get(player)
+	weapon := self.GetWeaponStats(component.Player.Get(player).EquippedWeapon())
+	pellets := max(weapon.Pellets, 1)
+
+	backwards := angle + math.Pi
+	for i := range pellets {
+		// From -0.5 for the leftmost pellet to 0.5 for the rightmost one.
+		t := 0.0
+		if pellets > 1 {
+			t = float64(i)/float64(pellets-1) - 0.5
+		}
 
-	self.FireBullet(player, bullet1)
-	self.FireBullet(player, bullet2)
+		bullet := *playerPosition
+		bullet.Angle = backwards + t*weapon.FanAngle
+		bullet.X -= 30 * t * math.Cos(backwards)
+		bullet.Y -= 30 * t * math.Sin(backwards)
+		bullet.Forward(-40)
+
+		entry := self.FireBullet(player, bullet)
+		if shot.MissileId != types.InvalidMissileId {
+			bulletData := component.Bullet.Get(entry)
+			bulletData.MissileId = shot.MissileId + types.MissileId(i)
+			bulletData.TargetId = shot.TargetId
+		}
+	}
 }
 
 func (self *GameSimulation) FireBullet(player *donburi.Entry, bulletPosition component.PositionData) *donburi.Entry {
 	playerData := component.Player.Get(player)
+	weapon := self.GetWeaponStats(playerData.EquippedWeapon())
 
-	entity := self.ECS.World.Create(component.Bullet, component.Sprite, component.Position, component.Expirable)
-	bullet := self.ECS.World.Entry(entity)
+	bullet := self.bullets.Get()
 
 	component.Bullet.SetValue(
 		bullet,
 		component.BulletData{
-			FiredBy: playerData.Id,
+			FiredBy:  playerData.Id,
+			Team:     playerData.Team,
+			Speed:    weapon.BulletSpeed,
+			Damage:   weapon.Damage,
+			TargetId: types.InvalidPlayerId,
+			TurnRate: weapon.TurnRate,
+			Falloff:  weapon.Falloff,
 		},
 	)
 	component.Position.SetValue(
@@ -203,7 +512,7 @@ func (self *GameSimulation) FireBullet(player *donburi.Entry, bulletPosition com
 	)
 	component.Expirable.SetValue(
 		bullet,
-		component.NewExpirable(time.Second),
+		component.NewExpirable(weapon.Lifetime),
 	)
 	component.Sprite.SetValue(
 		bullet,
@@ -213,38 +522,170 @@ func (self *GameSimulation) FireBullet(player *donburi.Entry, bulletPosition com
 	return bullet
 }
 
+func (self *GameSimulation) hitPosition(player *donburi.Entry, shooter types.PlayerId) *component.PositionData {
+	if self.RewindPlayer == nil {
+		return component.Position.Get(player)
+	}
+	return self.RewindPlayer(player, shooter)
+}
+
+// Removes the entity at the end of the current update, so that it is safe to
+// call while iterating over a query.
+func (self *GameSimulation) QueueRemoval(entry *donburi.Entry) {
+	self.removals[entry.Entity()] = entry
+}
+
+func (self *GameSimulation) IsQueuedForRemoval(entry *donburi.Entry) bool {
+	_, ok := self.removals[entry.Entity()]
+	return ok
+}
+
+// Removes the queued entities, skipping the ones something else already took
+// out of the world.
+func (self *GameSimulation) flushRemovals() {
+	removals := self.removals
+	self.removals = map[donburi.Entity]*donburi.Entry{}
+
+	for _, entry := range removals {
+		if entry.Valid() {
+			self.removeEntity(entry)
+		}
+	}
+}
+
+// Removes the entity from the world, bullets are put back into their pool.
+func (self *GameSimulation) removeEntity(entry *donburi.Entry) {
+	if entry.HasComponent(component.Bullet) {
+		self.bullets.Put(entry)
+		return
+	}
+	self.ECS.World.Remove(entry.Entity())
+}
+
+// Removes every bullet, asteroid and power-up, leaving the ships alone, for a
+// new round.
+func (self *GameSimulation) ClearWorld() {
+	cleared := []*donburi.Entry{}
+	query := donburi.NewQuery(filter.Or(
+		component.Active(component.Bullet),
+		filter.Contains(component.Asteroid),
+		filter.Contains(component.PowerUp),
+	))
+	for entry := range query.Iter(self.ECS.World) {
+		cleared = append(cleared, entry)
+	}
+
+	// Whatever was queued for removal among them is skipped once the queue
+	// is flushed.
+	for _, entry := range cleared {
+		self.removeEntity(entry)
+	}
+}
+
 func (self *GameSimulation) RespawnPlayer(player *donburi.Entry, newPosition component.PositionData) {
 	playerData := component.Player.Get(player)
-	playerData.Health = 100
+	playerData.Health = GetShipStats(playerData.Class).MaxHealth
+	playerData.Shield = MaxShield
 	playerData.IsAlive = true
 	component.Position.SetValue(player, newPosition)
 }
 
-func (self *GameSimulation) CreatePlayer(playerId types.PlayerId, position *component.PositionData, playerName string, IsConnected bool) *donburi.Entry {
+func (self *GameSimulation) CreatePlayer(playerId types.PlayerId, position *component.PositionData, playerName string, IsConnected bool, team types.Team, class types.ShipClass) *donburi.Entry {
 	entity := self.ECS.World.Create(component.Player, component.Position, component.Animation, component.Sprite)
 	player := self.ECS.World.Entry(entity)
 
 	playerData := component.PlayerData{
 		Name:        playerName,
 		Id:          playerId,
-		Health:      100,
+		Team:        team,
+		Class:       class,
+		Health:      GetShipStats(class).MaxHealth,
+		Shield:      MaxShield,
 		IsAlive:     true,
 		IsConnected: IsConnected,
 	}
 
 	component.Player.SetValue(player, playerData)
 	component.Position.SetValue(player, *position)
-	component.Sprite.SetValue(player, getShipSprite(playerId))
+	component.Sprite.SetValue(player, GetShipSprite(playerId, team, class))
 	component.Animation.SetValue(player, component.NewAnimationData(assets.OrangeExhaustAnimation[0], 5))
 
 	return player
 }
 
+func (self *GameSimulation) CreateAsteroid(asteroidId types.AsteroidId, position *component.PositionData, radius float64) *donburi.Entry {
+	entity := self.ECS.World.Create(component.Asteroid, component.Position, component.Sprite)
+	asteroid := self.ECS.World.Entry(entity)
+
+	component.Asteroid.SetValue(asteroid, component.AsteroidData{Id: asteroidId, Radius: radius})
+	component.Position.SetValue(asteroid, *position)
+	component.Sprite.SetValue(asteroid, assets.Asteroid)
+
+	return asteroid
+}
+
+func (self *GameSimulation) CreatePowerUp(powerUpId types.PowerUpId, position *component.PositionData, kind types.PowerUpKind) *donburi.Entry {
+	entity := self.ECS.World.Create(component.PowerUp, component.Position, component.Sprite)
+	powerUp := self.ECS.World.Entry(entity)
+
+	component.PowerUp.SetValue(powerUp, component.PowerUpData{Id: powerUpId, Kind: kind})
+	component.Position.SetValue(powerUp, *position)
+	component.Sprite.SetValue(powerUp, getPowerUpSprite(kind))
+
+	return powerUp
+}
+
+// Returns the ecs entry given the powerUpId.
+func (self *GameSimulation) FindCorrespondingPowerUp(powerUpId types.PowerUpId) *donburi.Entry {
+	query := donburi.NewQuery(filter.Contains(component.PowerUp))
+	for powerUp := range query.Iter(self.ECS.World) {
+		// Entities queued for removal are as good as gone.
+		if powerUpId == component.PowerUp.GetValue(powerUp).Id && !self.IsQueuedForRemoval(powerUp) {
+			return powerUp
+		}
+	}
+	return nil
+}
+
+// Grants the effect of the power-up to the player.
+func (self *GameSimulation) ApplyPowerUp(player *donburi.Entry, kind types.PowerUpKind) {
+	playerData := component.Player.Get(player)
+
+	switch kind {
+	case types.PowerUpShield:
+		playerData.Shield = MaxShield
+	case types.PowerUpRapidFire:
+		playerData.RapidFireExpiresWhen = time.Now().Add(PowerUpDuration)
+	case types.PowerUpSpeedBoost:
+		playerData.SpeedBoostExpiresWhen = time.Now().Add(PowerUpDuration)
+	// Picking up a weapon drops the one granted by the last power-up.
+	case types.PowerUpShotgun:
+		playerData.ShotgunExpiresWhen = time.Now().Add(PowerUpDuration)
+		playerData.MissileExpiresWhen = time.Time{}
+	case types.PowerUpMissile:
+		playerData.MissileExpiresWhen = time.Now().Add(PowerUpDuration)
+		playerData.ShotgunExpiresWhen = time.Time{}
+	}
+}
+
+// Returns the ecs entry given the asteroidId.
+func (self *GameSimulation) FindCorrespondingAsteroid(asteroidId types.AsteroidId) *donburi.Entry {
+	query := donburi.NewQuery(filter.Contains(component.Asteroid))
+	for asteroid := range query.Iter(self.ECS.World) {
+		// Entities queued for removal are as good as gone.
+		if asteroidId == component.Asteroid.GetValue(asteroid).Id && !self.IsQueuedForRemoval(asteroid) {
+			return asteroid
+		}
+	}
+	return nil
+}
+
 // Returns the ecs entry given the playerId.
 func (self *GameSimulation) FindCorrespondingPlayer(playerId types.PlayerId) *donburi.Entry {
 	query := donburi.NewQuery(filter.Contains(component.Player))
 	for player := range query.Iter(self.ECS.World) {
-		if playerId == component.Player.GetValue(player).Id {
+		// Entities queued for removal are as good as gone.
+		if playerId == component.Player.GetValue(player).Id && !self.IsQueuedForRemoval(player) {
 			return player
 		}
 	}
@@ -276,17 +717,183 @@ func (self *GameSimulation) spawnExplosion(position *component.PositionData) {
 	)
 }
 
-func GenerateRandomPlayerPosition() component.PositionData {
+func (self *GameSimulation) GenerateRandomPlayerPosition() component.PositionData {
 	return component.PositionData{
-		X:     generateRandomFloat(ShipWidth, 0.80*MapWidth),
-		Y:     generateRandomFloat(ShipHeight, 0.80*MapHeight),
+		X:     generateRandomFloat(ShipWidth, 0.80*self.MapWidth),
+		Y:     generateRandomFloat(ShipHeight, 0.80*self.MapHeight),
 		Angle: generateRandomFloat(0, 1),
 	}
 }
 
-func getShipSprite(playerId types.PlayerId) *ebiten.Image {
-	i := int(playerId)
-	return assets.Ships.GetTile(assets.TileIndex{X: 1, Y: i % 5})
+// Returns the position and radius of a new asteroid drifting in a random
+// direction.
+func (self *GameSimulation) GenerateRandomAsteroid() (component.PositionData, float64) {
+	angle := generateRandomFloat(0, 2*math.Pi)
+	speed := generateRandomFloat(AsteroidMaxSpeed/4, AsteroidMaxSpeed*3/4)
+
+	position := component.PositionData{
+		X:         generateRandomFloat(0, self.MapWidth),
+		Y:         generateRandomFloat(0, self.MapHeight),
+		Angle:     angle,
+		VelocityX: speed * math.Sin(angle),
+		VelocityY: -speed * math.Cos(angle),
+	}
+	return position, generateRandomFloat(AsteroidMinRadius, AsteroidMaxRadius-AsteroidMinRadius)
+}
+
+func (self *GameSimulation) GenerateRandomPowerUpPosition() component.PositionData {
+	return component.PositionData{
+		X: generateRandomFloat(ShipWidth, 0.80*self.MapWidth),
+		Y: generateRandomFloat(ShipHeight, 0.80*self.MapHeight),
+	}
+}
+
+func (self *GameSimulation) isInsideMap(position *component.PositionData) bool {
+	return position.X >= 0 && position.X <= self.MapWidth && position.Y >= 0 && position.Y <= self.MapHeight
+}
+
+// Keeps the ship inside the map. Hitting a wall only cancels the velocity
+// going into it so that the ship slides along the wall.
+func (self *GameSimulation) clampToMap(position *component.PositionData) {
+	self.recoverPosition(position)
+
+	if position.X < ShipWidth {
+		position.X = ShipWidth
+		position.VelocityX = math.Max(position.VelocityX, 0)
+	} else if position.X > self.MapWidth-ShipWidth {
+		position.X = self.MapWidth - ShipWidth
+		position.VelocityX = math.Min(position.VelocityX, 0)
+	}
+
+	if position.Y < ShipHeight {
+		position.Y = ShipHeight
+		position.VelocityY = math.Max(position.VelocityY, 0)
+	} else if position.Y > self.MapHeight-ShipHeight {
+		position.Y = self.MapHeight - ShipHeight
+		position.VelocityY = math.Min(position.VelocityY, 0)
+	}
+}
+
+// Brings a position that left the map back through the opposite edge.
+func (self *GameSimulation) wrapToMap(position *component.PositionData) {
+	self.recoverPosition(position)
+	position.X = wrapCoordinate(position.X, self.MapWidth)
+	position.Y = wrapCoordinate(position.Y, self.MapHeight)
+}
+
+// Wraps the coordinate into [0, size). Adding the size to a coordinate just
+// below 0 can round up to the size itself, which belongs to 0 instead.
+func wrapCoordinate(value float64, size float64) float64 {
+	value = math.Mod(value, size)
+	if value < 0 {
+		value += size
+	}
+	if value >= size {
+		value = 0
+	}
+	return value
+}
+
+// Puts a position that became NaN or infinite back on the map, the velocity
+// is dropped and the angle kept within [0, 2π). Once a NaN gets in it spreads
+// to everything it touches, so it is caught before the position is kept.
+func (self *GameSimulation) recoverPosition(position *component.PositionData) {
+	if !isFinite(position.X) || !isFinite(position.Y) {
+		position.X = self.MapWidth / 2
+		position.Y = self.MapHeight / 2
+	}
+	if !isFinite(position.VelocityX) || !isFinite(position.VelocityY) {
+		position.VelocityX = 0
+		position.VelocityY = 0
+	}
+	if !isFinite(position.Angle) {
+		position.Angle = 0
+	}
+	position.Angle = component.WrapAngle(position.Angle)
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// Returns the shortest signed distance covered by `delta` along an axis of
+// the given size that wraps around.
+func WrapDelta(delta float64, size float64) float64 {
+	return delta - size*math.Round(delta/size)
+}
+
+func getPowerUpSprite(kind types.PowerUpKind) *ebiten.Image {
+	switch kind {
+	case types.PowerUpRapidFire:
+		return assets.RapidFirePowerUp
+	case types.PowerUpSpeedBoost:
+		return assets.SpeedBoostPowerUp
+	case types.PowerUpShotgun:
+		return assets.ShotgunPowerUp
+	case types.PowerUpMissile:
+		return assets.MissilePowerUp
+	default:
+		return assets.ShieldPowerUp
+	}
+}
+
+// Ships are colored by team, or by id outside of the team mode, and shaped
+// by their class.
+func GetShipSprite(playerId types.PlayerId, team types.Team, class types.ShipClass) *ebiten.Image {
+	column := map[types.ShipClass]int{
+		types.ShipScout:   0,
+		types.ShipFighter: 1,
+		types.ShipTank:    2,
+	}[class]
+
+	switch team {
+	case types.TeamRed:
+		return assets.Ships.GetTile(assets.TileIndex{X: column, Y: 4})
+	case types.TeamBlue:
+		return assets.Ships.GetTile(assets.TileIndex{X: column, Y: 3})
+	}
+
+	return assets.Ships.GetTile(assets.TileIndex{X: column, Y: shipColor(playerId)})
+}
+
+// The ship sprites come in this many colors, the rows of the sheet past them
+// are used for the teams.
+const ShipColorCount = 5
+
+// Tints telling apart the ships that share a color once there are more players
+// than colors, the first lap of players is left as is.
+var shipTints = [][3]float32{
+	{1, 1, 1},
+	{0.6, 0.6, 0.6},
+	{1, 0.8, 0.5},
+	{0.6, 0.9, 1},
+}
+
+func shipColor(playerId types.PlayerId) int {
+	return int(playerId%ShipColorCount+ShipColorCount) % ShipColorCount
+}
+
+// Returns the tint the ship of the player is drawn with on top of its color.
+func GetShipTint(playerId types.PlayerId, team types.Team) ebiten.ColorScale {
+	var tint ebiten.ColorScale
+	if team != types.TeamNone || playerId < 0 {
+		return tint
+	}
+
+	lap := int(playerId/ShipColorCount) % len(shipTints)
+	tint.Scale(shipTints[lap][0], shipTints[lap][1], shipTints[lap][2], 1)
+	return tint
+}
+
+// Bullets spawn right next to the ship that fired them, and may circle back to
+// it on a wrapping map.
+func isOwnBullet(bullet *component.BulletData, player *component.PlayerData) bool {
+	return bullet.FiredBy == player.Id
+}
+
+// Teammates cannot shoot each other down.
+func isFriendlyFire(bullet *component.BulletData, player *component.PlayerData) bool {
+	return bullet.Team != types.TeamNone && bullet.Team == player.Team
 }
 
 func generateRandomFloat(min, max float64) float64 {
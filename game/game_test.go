@@ -0,0 +1,16 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"fmt"
+
+	"github.com/yohamta/donburi"
+)
+
+// Creates a connected ship of the default class, without a team, at the
+// given position.
+func newTestPlayer(simulation *GameSimulation, playerId types.PlayerId, x, y float64) *donburi.Entry {
+	position := component.PositionData{X: x, Y: y}
+	return simulation.CreatePlayer(playerId, &position, fmt.Sprintf("Player %d", playerId), true, types.TeamNone, types.ShipFighter)
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"math"
+
+	"github.com/yohamta/donburi"
+)
+
+// Bigger than the largest collision radius so that a query never spans more
+// than a couple of cells on each axis.
+const gridCellSize = 256
+
+type gridCell struct {
+	X, Y int
+}
+
+// Buckets the ships by their position so that collision checks only look at
+// the ships close by instead of every ship on the map.
+type spatialGrid struct {
+	cells map[gridCell][]*donburi.Entry
+
+	// How many cells span the map, for wrapping the queries around its
+	// edges.
+	columns, rows int
+
+	// Whether queries near an edge of the map also look at the cells on the
+	// other side of it.
+	wraps bool
+}
+
+func newSpatialGrid() *spatialGrid {
+	return &spatialGrid{cells: make(map[gridCell][]*donburi.Entry)}
+}
+
+// Covers a map of the given size, wrapping the queries around its edges if
+// the map wraps.
+func (self *spatialGrid) resize(width, height float64, wraps bool) {
+	self.columns = int(math.Ceil(width / gridCellSize))
+	self.rows = int(math.Ceil(height / gridCellSize))
+	self.wraps = wraps
+}
+
+// Empties the grid while keeping the buckets around for the next tick.
+func (self *spatialGrid) clear() {
+	for cell, entries := range self.cells {
+		self.cells[cell] = entries[:0]
+	}
+}
+
+func (self *spatialGrid) insert(entry *donburi.Entry, position *component.PositionData) {
+	cell := cellAt(position.X, position.Y)
+	self.cells[cell] = append(self.cells[cell], entry)
+}
+
+// Calls `visit` with every entry that may be within `radius` of the position
+// and returns the first one it accepts, or nil if none does.
+func (self *spatialGrid) find(position *component.PositionData, radius float64, visit func(entry *donburi.Entry) bool) *donburi.Entry {
+	first := cellAt(position.X-radius, position.Y-radius)
+	last := cellAt(position.X+radius, position.Y+radius)
+
+	// Past an edge the cells continue from the other side, no more than
+	// every cell once.
+	if self.wraps {
+		last.X = min(last.X, first.X+self.columns-1)
+		last.Y = min(last.Y, first.Y+self.rows-1)
+	}
+
+	for x := first.X; x <= last.X; x++ {
+		for y := first.Y; y <= last.Y; y++ {
+			cell := gridCell{x, y}
+			if self.wraps {
+				cell = gridCell{wrapCell(x, self.columns), wrapCell(y, self.rows)}
+			}
+
+			for _, entry := range self.cells[cell] {
+				if visit(entry) {
+					return entry
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func cellAt(x, y float64) gridCell {
+	return gridCell{int(math.Floor(x / gridCellSize)), int(math.Floor(y / gridCellSize))}
+}
+
+func wrapCell(index int, count int) int {
+	return ((index % count) + count) % count
+}
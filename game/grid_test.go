@@ -0,0 +1,110 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/yohamta/donburi"
+)
+
+// Creates ships at random positions on the map, along with a grid holding
+// them.
+func createTargets(simulation *GameSimulation, count int) ([]*donburi.Entry, *spatialGrid) {
+	grid := newSpatialGrid()
+	grid.resize(simulation.MapWidth, simulation.MapHeight, simulation.WorldMode == types.WorldWrap)
+
+	targets := make([]*donburi.Entry, count)
+	for i := range targets {
+		targets[i] = newTestPlayer(simulation, types.PlayerId(i), rand.Float64()*simulation.MapWidth, rand.Float64()*simulation.MapHeight)
+		grid.insert(targets[i], component.Position.Get(targets[i]))
+	}
+	return targets, grid
+}
+
+func TestGridFindsAcrossEdges(t *testing.T) {
+	simulation := NewGameSimulation()
+	simulation.WorldMode = types.WorldWrap
+
+	ship := newTestPlayer(simulation, 0, 10, 10)
+
+	grid := newSpatialGrid()
+	grid.resize(simulation.MapWidth, simulation.MapHeight, true)
+	grid.insert(ship, component.Position.Get(ship))
+
+	// Every corner of the map touches the others when it wraps.
+	queries := []component.PositionData{
+		{X: simulation.MapWidth - 10, Y: 10},
+		{X: 10, Y: simulation.MapHeight - 10},
+		{X: simulation.MapWidth - 10, Y: simulation.MapHeight - 10},
+	}
+	for _, query := range queries {
+		found := grid.find(&query, 30, func(entry *donburi.Entry) bool {
+			return simulation.intersects(component.Position.Get(entry), &query, 30)
+		})
+		if found != ship {
+			t.Errorf("The ship at (10, 10) wasn't found from (%g, %g)", query.X, query.Y)
+		}
+	}
+}
+
+func TestGridMatchesBruteForce(t *testing.T) {
+	for _, mode := range []types.WorldMode{types.WorldClamp, types.WorldWrap} {
+		simulation := NewGameSimulation()
+		simulation.WorldMode = mode
+		targets, grid := createTargets(simulation, 200)
+
+		for range 1000 {
+			query := component.PositionData{X: rand.Float64() * simulation.MapWidth, Y: rand.Float64() * simulation.MapHeight}
+			radius := 20 + rand.Float64()*200
+
+			expected := 0
+			for _, target := range targets {
+				if simulation.intersects(component.Position.Get(target), &query, radius) {
+					expected++
+				}
+			}
+
+			found := 0
+			grid.find(&query, radius, func(entry *donburi.Entry) bool {
+				if simulation.intersects(component.Position.Get(entry), &query, radius) {
+					found++
+				}
+				return false
+			})
+
+			if found != expected {
+				t.Fatalf("The grid found %d ships within %g of (%g, %g) in mode %v, expected %d", found, radius, query.X, query.Y, mode, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkGrid(b *testing.B) {
+	for _, count := range []int{16, 64, 256} {
+		simulation := NewGameSimulation()
+		simulation.WorldMode = types.WorldWrap
+		targets, grid := createTargets(simulation, count)
+		query := component.PositionData{X: simulation.MapWidth / 2, Y: simulation.MapHeight / 2}
+
+		b.Run(fmt.Sprintf("Grid/%d", count), func(b *testing.B) {
+			for range b.N {
+				grid.find(&query, 40, func(entry *donburi.Entry) bool {
+					return simulation.intersects(component.Position.Get(entry), &query, 40)
+				})
+			}
+		})
+
+		b.Run(fmt.Sprintf("BruteForce/%d", count), func(b *testing.B) {
+			for range b.N {
+				for _, target := range targets {
+					if simulation.intersects(component.Position.Get(target), &query, 40) {
+						break
+					}
+				}
+			}
+		})
+	}
+}
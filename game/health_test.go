@@ -0,0 +1,51 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"testing"
+)
+
+func TestSetPlayerHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		health   float64
+		expected func(maxHealth float64) float64
+	}{
+		{name: "hurt", health: 40, expected: func(float64) float64 { return 40 }},
+		{name: "killed", health: 0, expected: func(float64) float64 { return 0 }},
+		{name: "overkill", health: -500, expected: func(float64) float64 { return 0 }},
+		{name: "full health", health: 70, expected: func(maxHealth float64) float64 { return min(70, maxHealth) }},
+		{name: "overheal", health: 1000, expected: func(maxHealth float64) float64 { return maxHealth }},
+	}
+
+	for class := range types.ShipClassCount {
+		maxHealth := GetShipStats(class).MaxHealth
+		for _, test := range tests {
+			playerData := component.PlayerData{Class: class, Health: maxHealth, IsAlive: true}
+			SetPlayerHealth(&playerData, test.health)
+			if expected := test.expected(maxHealth); playerData.Health != expected {
+				t.Errorf("Setting the health of a %s %s gave %g, expected %g", class, test.name, playerData.Health, expected)
+			}
+		}
+	}
+}
+
+func TestDoubleDeath(t *testing.T) {
+	simulation := NewGameSimulation()
+	victim := newTestPlayer(simulation, 0, 100, 100)
+	victimData := component.Player.Get(victim)
+
+	// A second lethal hit on a ship that is already down leaves it at 0.
+	victimData.TakeDamage(1000)
+	SetPlayerHealth(victimData, victimData.Health)
+	simulation.RegisterPlayerDeath(victim, nil)
+
+	victimData.TakeDamage(1000)
+	SetPlayerHealth(victimData, victimData.Health)
+	simulation.UpdatePlayerHealth(0, -1000)
+
+	if victimData.Health != 0 || victimData.Shield != 0 || victimData.IsAlive {
+		t.Errorf("After dying twice the ship has %g health and %g shield, alive: %t", victimData.Health, victimData.Shield, victimData.IsAlive)
+	}
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"math"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Missiles only lock on to enemies closer than this when fired.
+const MissileLockRange = 1500
+
+// A shot of the equipped weapon, the server decides where it goes and tells
+// the clients.
+type Shot struct {
+	Angle float64
+
+	// The enemy the missiles of the shot home in on, and the id of the first
+	// missile, the others follow in order. Unused by weapons that don't home.
+	TargetId  types.PlayerId
+	MissileId types.MissileId
+}
+
+// Returns the closest enemy within lock range for the missiles of the player,
+// or `types.InvalidPlayerId` if there is none.
+func (self *GameSimulation) FindMissileTarget(player *donburi.Entry) types.PlayerId {
+	shooter := component.Player.Get(player)
+	position := component.Position.Get(player)
+
+	target := types.InvalidPlayerId
+	closest := float64(MissileLockRange)
+
+	for other := range donburi.NewQuery(filter.Contains(component.Player, component.Position)).Iter(self.ECS.World) {
+		data := component.Player.Get(other)
+		if data.Id == shooter.Id || !data.IsAlive || !data.IsConnected {
+			continue
+		}
+		if data.Team != types.TeamNone && data.Team == shooter.Team {
+			continue
+		}
+
+		dx, dy := self.delta(position, component.Position.Get(other))
+		if distance := math.Hypot(dx, dy); distance < closest {
+			target, closest = data.Id, distance
+		}
+	}
+	return target
+}
+
+// Turns a missile towards its target, a missile whose target died loses its
+// lock and flies straight from then on.
+func (self *GameSimulation) steerMissile(bullet *component.BulletData, position *component.PositionData, dt float64) {
+	if bullet.TargetId == types.InvalidPlayerId || bullet.TurnRate == 0 {
+		return
+	}
+
+	target := self.FindCorrespondingPlayer(bullet.TargetId)
+	if target == nil || !component.Player.Get(target).IsAlive {
+		bullet.TargetId = types.InvalidPlayerId
+		return
+	}
+
+	dx, dy := self.delta(position, component.Position.Get(target))
+
+	// Bullets point backwards, they move along the opposite of their angle.
+	position.RotateToward(math.Atan2(dx, -dy)+math.Pi, bullet.TurnRate*dt)
+}
+
+// Returns the offset from one position to the other, the short way around
+// when the map wraps.
+func (self *GameSimulation) delta(from *component.PositionData, to *component.PositionData) (float64, float64) {
+	dx := to.X - from.X
+	dy := to.Y - from.Y
+	if self.WorldMode == types.WorldWrap {
+		dx = WrapDelta(dx, self.MapWidth)
+		dy = WrapDelta(dy, self.MapHeight)
+	}
+	return dx, dy
+}
+
+// Whether the positions are within `radius` of each other, across the edges
+// when the map wraps.
+func (self *GameSimulation) intersects(from *component.PositionData, to *component.PositionData, radius float64) bool {
+	dx, dy := self.delta(from, to)
+	return dx*dx+dy*dy <= radius*radius
+}
+
+// Returns the missile with the given id, if it is still flying.
+func (self *GameSimulation) FindCorrespondingMissile(missileId types.MissileId) *donburi.Entry {
+	for bullet := range donburi.NewQuery(component.Active(component.Bullet)).Iter(self.ECS.World) {
+		if component.Bullet.Get(bullet).MissileId == missileId {
+			return bullet
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"testing"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+func TestQueueRemovalDuringQuery(t *testing.T) {
+	simulation := NewGameSimulation()
+	for id := range types.AsteroidId(100) {
+		simulation.CreateAsteroid(id, &component.PositionData{X: float64(id)}, AsteroidMinRadius)
+	}
+
+	// Remove every other asteroid while going through all of them.
+	visits := map[types.AsteroidId]int{}
+	for asteroid := range donburi.NewQuery(filter.Contains(component.Asteroid)).Iter(simulation.ECS.World) {
+		id := component.Asteroid.Get(asteroid).Id
+		visits[id]++
+		if id%2 == 0 {
+			simulation.QueueRemoval(asteroid)
+		}
+	}
+
+	for id := range types.AsteroidId(100) {
+		if visits[id] != 1 {
+			t.Errorf("Asteroid %d was visited %d times, expected once", id, visits[id])
+		}
+	}
+
+	simulation.flushRemovals()
+	for id := range types.AsteroidId(100) {
+		isRemoved := simulation.FindCorrespondingAsteroid(id) == nil
+		if isRemoved != (id%2 == 0) {
+			t.Errorf("Asteroid %d was removed: %t, expected %t", id, isRemoved, id%2 == 0)
+		}
+	}
+}
+
+func TestFindSkipsQueuedRemovals(t *testing.T) {
+	simulation := NewGameSimulation()
+	asteroid := simulation.CreateAsteroid(1, &component.PositionData{}, AsteroidMinRadius)
+
+	simulation.QueueRemoval(asteroid)
+	if simulation.FindCorrespondingAsteroid(1) != nil {
+		t.Errorf("Found an asteroid queued for removal")
+	}
+}
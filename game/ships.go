@@ -0,0 +1,45 @@
+package game
+
+import "astro-blasters/game/types"
+
+type ShipStats struct {
+	// The thrust tapers off near the top speed, which is never exceeded.
+	Acceleration  float64
+	MaxSpeed      float64
+	RotationSpeed float64
+	MaxHealth     float64
+
+	// Scales the fire cooldown, ships below 1 fire faster.
+	FireCooldownScale float64
+}
+
+var shipStats = map[types.ShipClass]ShipStats{
+	types.ShipFighter: {
+		Acceleration:      PlayerAcceleration,
+		MaxSpeed:          PlayerMaxSpeed,
+		RotationSpeed:     PlayerRotationSpeed,
+		MaxHealth:         100,
+		FireCooldownScale: 1,
+	},
+	types.ShipScout: {
+		Acceleration:      1260,
+		MaxSpeed:          500,
+		RotationSpeed:     390,
+		MaxHealth:         70,
+		FireCooldownScale: 1.25,
+	},
+	types.ShipTank: {
+		Acceleration:      648,
+		MaxSpeed:          260,
+		RotationSpeed:     210,
+		MaxHealth:         150,
+		FireCooldownScale: 0.8,
+	},
+}
+
+func GetShipStats(class types.ShipClass) ShipStats {
+	if stats, ok := shipStats[class]; ok {
+		return stats
+	}
+	return shipStats[types.ShipFighter]
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Fails unless the position is finite, on the map and facing within [0, 2π).
+func checkPosition(t *testing.T, step int, mode types.WorldMode, position *component.PositionData) {
+	t.Helper()
+
+	minX, maxX, minY, maxY := 0.0, float64(DefaultMapWidth), 0.0, float64(DefaultMapHeight)
+	if mode == types.WorldClamp {
+		minX, maxX, minY, maxY = ShipWidth, DefaultMapWidth-ShipWidth, ShipHeight, DefaultMapHeight-ShipHeight
+	}
+
+	isOnMap := position.X >= minX && position.X <= maxX && position.Y >= minY && position.Y <= maxY
+	if mode == types.WorldWrap {
+		isOnMap = isOnMap && position.X < DefaultMapWidth && position.Y < DefaultMapHeight
+	}
+
+	if !isOnMap || !isFinite(position.VelocityX) || !isFinite(position.VelocityY) {
+		t.Fatalf("After %d steps in %s the ship is at (%g, %g) moving at (%g, %g)", step, mode, position.X, position.Y, position.VelocityX, position.VelocityY)
+	}
+	if position.Angle < 0 || position.Angle >= 2*math.Pi {
+		t.Fatalf("After %d steps in %s the ship faces %g", step, mode, position.Angle)
+	}
+}
+
+func TestMillionsOfSteps(t *testing.T) {
+	steps := 2_000_000
+	if testing.Short() {
+		steps = 100_000
+	}
+
+	simulation := NewGameSimulation()
+	for _, mode := range []types.WorldMode{types.WorldClamp, types.WorldWrap} {
+		random := rand.New(rand.NewSource(1))
+		position := component.PositionData{X: DefaultMapWidth / 2, Y: DefaultMapHeight / 2}
+
+		// Random inputs, including ones no client would send.
+		for step := range steps {
+			switch random.Intn(1000) {
+			case 0:
+				position.Angle = math.NaN()
+			case 1:
+				position.VelocityX = math.Inf(1)
+			case 2:
+				position.X = math.Inf(-1)
+			case 3:
+				position.Angle = -1e-18
+			}
+
+			position.Thrust(random.Float64() * 20)
+			position.Rotate((random.Float64() - 0.5) * 30)
+			position.ApplyPhysics(PlayerDrag, 1.0/60)
+			position.LimitSpeed(2000)
+
+			if mode == types.WorldWrap {
+				simulation.wrapToMap(&position)
+			} else {
+				simulation.clampToMap(&position)
+			}
+			checkPosition(t, step, mode, &position)
+		}
+	}
+}
+
+func TestWrapCoordinate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		expected float64
+	}{
+		{name: "within the map", value: 100, expected: 100},
+		{name: "at 0", value: 0, expected: 0},
+		{name: "at the edge", value: DefaultMapWidth, expected: 0},
+		{name: "past the edge", value: DefaultMapWidth + 10, expected: 10},
+		{name: "below 0", value: -10, expected: DefaultMapWidth - 10},
+		{name: "tiny negative", value: -1e-18, expected: 0},
+		{name: "far past the edge", value: 1e300, expected: math.Mod(1e300, DefaultMapWidth)},
+	}
+
+	for _, test := range tests {
+		value := wrapCoordinate(test.value, DefaultMapWidth)
+		if value < 0 || value >= DefaultMapWidth {
+			t.Errorf("Wrapping %s gave %g, outside of the map", test.name, value)
+		}
+		if math.Abs(value-test.expected) > 1e-9 {
+			t.Errorf("Wrapping %s gave %g, expected %g", test.name, value, test.expected)
+		}
+	}
+}
+
+func TestRecoverPosition(t *testing.T) {
+	position := component.PositionData{X: math.NaN(), Y: 10, Angle: math.Inf(1), VelocityX: math.NaN(), VelocityY: 5}
+	NewGameSimulation().recoverPosition(&position)
+
+	expected := component.PositionData{X: DefaultMapWidth / 2, Y: DefaultMapHeight / 2}
+	if position != expected {
+		t.Errorf("Recovered to %+v, expected %+v", position, expected)
+	}
+}
+
+func TestSimulationStaysOnMap(t *testing.T) {
+	moves := []types.PlayerMove{
+		types.PlayerStartForward, types.PlayerStopForward,
+		types.PlayerStartRotateClockwise, types.PlayerStopRotateClockwise,
+		types.PlayerStartRotateCounterClockwise, types.PlayerStopRotateCounterClockwise,
+	}
+
+	for _, mode := range []types.WorldMode{types.WorldClamp, types.WorldWrap} {
+		random := rand.New(rand.NewSource(1))
+		simulation := NewGameSimulation()
+		simulation.WorldMode = mode
+
+		player := newTestPlayer(simulation, 0, simulation.MapWidth/2, simulation.MapHeight/2)
+
+		for step := range 50_000 {
+			if random.Intn(30) == 0 {
+				simulation.RegisterPlayerMove(0, moves[random.Intn(len(moves))])
+			}
+			simulation.Update(1.0 / 60)
+			checkPosition(t, step, mode, component.Position.Get(player))
+		}
+	}
+}
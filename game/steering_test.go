@@ -0,0 +1,38 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"math"
+	"testing"
+)
+
+func TestSteeringTurnsAtTheTurnRate(t *testing.T) {
+	simulation := NewGameSimulation()
+	player := newTestPlayer(simulation, 0, simulation.MapWidth/2, simulation.MapHeight/2)
+	turnRate := GetShipStats(types.ShipFighter).RotationSpeed * math.Pi / 180
+
+	// A quarter turn to the left is shorter than three quarters to the right.
+	target := 3 * math.Pi / 2
+	simulation.RegisterPlayerSteering(0, true, target)
+
+	simulation.Update(0.1)
+	expected := component.WrapAngle(-turnRate * 0.1)
+	if angle := component.Position.Get(player).Angle; math.Abs(component.AngleDifference(angle, expected)) > 1e-9 {
+		t.Errorf("Steering turned the ship to %g, expected %g", angle, expected)
+	}
+
+	for range 100 {
+		simulation.Update(0.1)
+	}
+	if angle := component.Position.Get(player).Angle; math.Abs(component.AngleDifference(angle, target)) > 1e-9 {
+		t.Errorf("Steering left the ship at %g, expected it to settle on %g", angle, target)
+	}
+
+	// Letting go of the stick keeps the heading.
+	simulation.RegisterPlayerSteering(0, false, 0)
+	simulation.Update(0.1)
+	if angle := component.Position.Get(player).Angle; math.Abs(component.AngleDifference(angle, target)) > 1e-9 {
+		t.Errorf("The ship kept turning to %g after steering stopped", angle)
+	}
+}
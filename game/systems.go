@@ -0,0 +1,39 @@
+package game
+
+import "slices"
+
+// The stages every update goes through, in order. Systems on the same stage
+// run in the order they were added.
+//
+// Entities that expired are queued for removal first, so that nothing else
+// touches them. The targets are then rebuilt from where the ships are, and
+// bullets, asteroids and power-ups are resolved against them before the ships
+// fire and move. Removals are only carried out at the very end, once nothing
+// is iterating over the world anymore.
+const (
+	stageExpiry = iota
+	stageTargets
+	stageCollision
+	stageMovement
+	stageCleanup
+)
+
+type system struct {
+	stage  int
+	update func(dt float64)
+}
+
+type systems []system
+
+func (self *systems) add(stage int, update func(dt float64)) {
+	*self = append(*self, system{stage: stage, update: update})
+	slices.SortStableFunc(*self, func(a, b system) int {
+		return a.stage - b.stage
+	})
+}
+
+func (self systems) update(dt float64) {
+	for _, system := range self {
+		system.update(dt)
+	}
+}
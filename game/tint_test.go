@@ -0,0 +1,45 @@
+package game
+
+import (
+	"astro-blasters/game/types"
+	"testing"
+)
+
+func TestShipTintsTellColorsApart(t *testing.T) {
+	type look struct {
+		color   int
+		r, g, b float32
+	}
+
+	// Every player up to the last tint gets a look of their own, even though
+	// the colors of the sprites repeat after the first few.
+	players := types.PlayerId(ShipColorCount * len(shipTints))
+	seen := map[look]types.PlayerId{}
+	for playerId := range players {
+		tint := GetShipTint(playerId, types.TeamNone)
+		key := look{shipColor(playerId), tint.R(), tint.G(), tint.B()}
+		if other, ok := seen[key]; ok {
+			t.Errorf("Players %d and %d look the same", other, playerId)
+		}
+		seen[key] = playerId
+	}
+
+	// The first lap of players is drawn as is.
+	for playerId := range types.PlayerId(ShipColorCount) {
+		tint := GetShipTint(playerId, types.TeamNone)
+		if tint.R() != 1 || tint.G() != 1 || tint.B() != 1 {
+			t.Errorf("Player %d is tinted (%g, %g, %g), expected no tint", playerId, tint.R(), tint.G(), tint.B())
+		}
+	}
+}
+
+func TestTeamShipsAreNotTinted(t *testing.T) {
+	for _, team := range []types.Team{types.TeamRed, types.TeamBlue} {
+		for playerId := range types.PlayerId(3 * ShipColorCount) {
+			tint := GetShipTint(playerId, team)
+			if tint.R() != 1 || tint.G() != 1 || tint.B() != 1 {
+				t.Errorf("Player %d of team %v is tinted (%g, %g, %g)", playerId, team, tint.R(), tint.G(), tint.B())
+			}
+		}
+	}
+}
@@ -15,4 +15,7 @@ const (
 
 	PlayerStartFireBullet
 	PlayerStopFireBullet
+
+	// Kicks the ship forward, once per `game.BoostCooldown`.
+	PlayerBoost
 )
@@ -0,0 +1,15 @@
+package types
+
+type PowerUpId int64
+
+type PowerUpKind int64
+
+const (
+	PowerUpShield PowerUpKind = iota
+	PowerUpRapidFire
+	PowerUpSpeedBoost
+	PowerUpShotgun
+	PowerUpMissile
+
+	PowerUpKindCount
+)
@@ -0,0 +1,22 @@
+package types
+
+type ShipClass int64
+
+const (
+	ShipFighter ShipClass = iota
+	ShipScout
+	ShipTank
+
+	ShipClassCount
+)
+
+func (self ShipClass) String() string {
+	switch self {
+	case ShipScout:
+		return "Scout"
+	case ShipTank:
+		return "Tank"
+	default:
+		return "Fighter"
+	}
+}
@@ -0,0 +1,21 @@
+package types
+
+type Team int64
+
+const (
+	// Players are on their own outside of the team mode.
+	TeamNone Team = iota
+	TeamRed
+	TeamBlue
+)
+
+func (self Team) String() string {
+	switch self {
+	case TeamRed:
+		return "Red"
+	case TeamBlue:
+		return "Blue"
+	default:
+		return "None"
+	}
+}
@@ -5,3 +5,13 @@ type PlayerId int64
 const (
 	InvalidPlayerId = PlayerId(-1)
 )
+
+type AsteroidId int64
+
+// Bullets that home in on a target are tracked by id so that the server can
+// correct them, other bullets have none.
+type MissileId int64
+
+const (
+	InvalidMissileId = MissileId(0)
+)
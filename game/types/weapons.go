@@ -0,0 +1,33 @@
+package types
+
+type WeaponId int64
+
+const (
+	WeaponBlaster WeaponId = iota
+	WeaponShotgun
+	WeaponMissile
+)
+
+func (self WeaponId) String() string {
+	switch self {
+	case WeaponShotgun:
+		return "Shotgun"
+	case WeaponMissile:
+		return "Missile"
+	default:
+		return "Blaster"
+	}
+}
+
+// Returns the weapon with the given name, as used in weapon config files.
+func ParseWeaponId(name string) (WeaponId, bool) {
+	for id := range WeaponCount {
+		if id.String() == name {
+			return id, true
+		}
+	}
+	return WeaponBlaster, false
+}
+
+// The number of weapons, one past the last weapon id.
+const WeaponCount = WeaponMissile + 1
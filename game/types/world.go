@@ -0,0 +1,20 @@
+package types
+
+// How the edges of the map behave.
+type WorldMode int64
+
+const (
+	// Ships stop at the edges of the map.
+	WorldClamp WorldMode = iota
+	// Ships leaving through an edge come back through the opposite one.
+	WorldWrap
+)
+
+func (self WorldMode) String() string {
+	switch self {
+	case WorldWrap:
+		return "Wrap"
+	default:
+		return "Clamp"
+	}
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type WeaponStats struct {
+	BulletSpeed float64 // px/s
+	Damage      float64
+
+	// The minimum time between two shots, before it is scaled by the ship.
+	Cooldown time.Duration
+
+	// How far, in radians, a shot may stray either way from where it was
+	// aimed.
+	Spread float64
+
+	// Each shot fires this many bullets side by side, fanned out over
+	// `FanAngle` radians.
+	Pellets  int
+	FanAngle float64
+
+	// How long the bullets fly before fading out.
+	Lifetime time.Duration
+
+	// How quickly, in radians per second, the bullets turn towards the
+	// enemy locked on when fired. 0 for bullets that fly straight.
+	TurnRate float64
+
+	// How the damage drops the further the bullets fly.
+	Falloff component.DamageFalloff
+}
+
+func DefaultWeapons() map[types.WeaponId]WeaponStats {
+	return map[types.WeaponId]WeaponStats{
+		types.WeaponBlaster: {
+			BulletSpeed: BulletSpeed,
+			Damage:      PlayerDamagePerHit,
+			Cooldown:    300 * time.Millisecond,
+			Spread:      0,
+			Pellets:     2,
+			FanAngle:    0,
+			Lifetime:    BulletLifetime,
+			Falloff:     component.DamageFalloff{Start: 600, End: 1200, MinFactor: 0.6},
+		},
+		types.WeaponShotgun: {
+			BulletSpeed: 1000,
+			Damage:      3,
+			Cooldown:    700 * time.Millisecond,
+			Spread:      0.05,
+			Pellets:     5,
+			FanAngle:    0.6,
+			Lifetime:    BulletLifetime,
+			Falloff:     component.DamageFalloff{Start: 200, End: 800, MinFactor: 0.3},
+		},
+		types.WeaponMissile: {
+			BulletSpeed: 700,
+			Damage:      12,
+			Cooldown:    time.Second,
+			Spread:      0,
+			Pellets:     1,
+			FanAngle:    0,
+			Lifetime:    3 * time.Second,
+			TurnRate:    3,
+		},
+	}
+}
+
+// The weapon definitions as written in a config file, cooldowns and lifetimes
+// are given as durations such as "300ms".
+type weaponConfig struct {
+	BulletSpeed *float64
+	Damage      *float64
+	Cooldown    *string
+	Spread      *float64
+	Pellets     *int
+	FanAngle    *float64
+	Lifetime    *string
+	TurnRate    *float64
+
+	// The falloff, in pixels and as a fraction of the damage.
+	FalloffStart    *float64
+	FalloffEnd      *float64
+	MinDamageFactor *float64
+}
+
+// Reads weapon definitions from a JSON file mapping weapon names to their
+// stats, e.g. `{"Blaster": {"Damage": 8, "Cooldown": "250ms"}}`. Weapons and
+// stats missing from the file keep their defaults.
+func LoadWeapons(path string) (map[types.WeaponId]WeaponStats, error) {
+	weapons := DefaultWeapons()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return weapons, fmt.Errorf("Failed to read weapons from %s: %w", path, err)
+	}
+
+	var configs map[string]weaponConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return weapons, fmt.Errorf("Failed to parse weapons from %s: %w", path, err)
+	}
+
+	for name, config := range configs {
+		id, ok := types.ParseWeaponId(name)
+		if !ok {
+			return weapons, fmt.Errorf("Unknown weapon %q in %s", name, path)
+		}
+
+		stats := weapons[id]
+		if config.BulletSpeed != nil {
+			stats.BulletSpeed = *config.BulletSpeed
+		}
+		if config.Damage != nil {
+			stats.Damage = *config.Damage
+		}
+		if config.Cooldown != nil {
+			cooldown, err := time.ParseDuration(*config.Cooldown)
+			if err != nil {
+				return weapons, fmt.Errorf("Invalid cooldown for %s in %s: %w", name, path, err)
+			}
+			stats.Cooldown = cooldown
+		}
+		if config.Spread != nil {
+			stats.Spread = *config.Spread
+		}
+		if config.Pellets != nil {
+			stats.Pellets = max(*config.Pellets, 1)
+		}
+		if config.FanAngle != nil {
+			stats.FanAngle = *config.FanAngle
+		}
+		if config.Lifetime != nil {
+			lifetime, err := time.ParseDuration(*config.Lifetime)
+			if err != nil {
+				return weapons, fmt.Errorf("Invalid lifetime for %s in %s: %w", name, path, err)
+			}
+			stats.Lifetime = lifetime
+		}
+		if config.TurnRate != nil {
+			stats.TurnRate = *config.TurnRate
+		}
+		if config.FalloffStart != nil {
+			stats.Falloff.Start = *config.FalloffStart
+		}
+		if config.FalloffEnd != nil {
+			stats.Falloff.End = *config.FalloffEnd
+		}
+		if config.MinDamageFactor != nil {
+			if *config.MinDamageFactor < 0 || *config.MinDamageFactor > 1 {
+				return weapons, fmt.Errorf("Invalid minimum damage factor for %s in %s, it should be from 0 to 1", name, path)
+			}
+			stats.Falloff.MinFactor = *config.MinDamageFactor
+		}
+		weapons[id] = stats
+	}
+	return weapons, nil
+}
+
+// Returns the stats of the weapon, unknown weapons fire like a blaster.
+func (self *GameSimulation) GetWeaponStats(weapon types.WeaponId) WeaponStats {
+	if stats, ok := self.Weapons[weapon]; ok {
+		return stats
+	}
+	return DefaultWeapons()[types.WeaponBlaster]
+}
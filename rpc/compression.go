@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Payloads smaller than this aren't worth the time spent compressing them.
+const CompressionThreshold = 512
+
+var flateWriterPool = sync.Pool{
+	New: func() interface{} {
+		writer, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return writer
+	},
+}
+
+// Returns the message with its payload compressed, or the message as is when
+// compressing wouldn't make it any smaller. Only peers that said they
+// support compression during the handshake should be sent compressed
+// messages.
+func CompressMessage(message BaseMessage) BaseMessage {
+	if message.IsCompressed || len(message.Payload) < CompressionThreshold {
+		return message
+	}
+
+	var buffer bytes.Buffer
+	writer := flateWriterPool.Get().(*flate.Writer)
+	defer flateWriterPool.Put(writer)
+
+	writer.Reset(&buffer)
+	if _, err := writer.Write(message.Payload); err != nil {
+		return message
+	}
+	if err := writer.Close(); err != nil {
+		return message
+	}
+
+	if buffer.Len() >= len(message.Payload) {
+		return message
+	}
+
+	// The payload is embedded as is in the message, so the compressed bytes
+	// have to be wrapped as msgpack binary first.
+	payload, err := msgpack.Marshal(buffer.Bytes())
+	if err != nil {
+		return message
+	}
+	return BaseMessage{
+		MessageType:  message.MessageType,
+		Payload:      payload,
+		IsCompressed: true,
+	}
+}
+
+// Restores the payload of a compressed message.
+func decompressMessage(message *BaseMessage) error {
+	var compressed []byte
+	if err := msgpack.Unmarshal(message.Payload, &compressed); err != nil {
+		return err
+	}
+
+	reader := flate.NewReader(bytes.NewReader(compressed))
+	defer reader.Close()
+
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	message.Payload = payload
+	message.IsCompressed = false
+	return nil
+}
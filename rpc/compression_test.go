@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestCompressedMessageRoundTrip(t *testing.T) {
+	message := NewBaseMessage(struct{ Text string }{strings.Repeat("pew ", CompressionThreshold)})
+
+	compressed := CompressMessage(message)
+	if !compressed.IsCompressed {
+		t.Fatal("A large repetitive message wasn't compressed")
+	}
+
+	encoded, err := EncodeMessage(compressed)
+	if err != nil {
+		t.Fatalf("Failed to encode the compressed message: %s", err)
+	}
+
+	var received BaseMessage
+	if err := msgpack.Unmarshal(encoded.data, &received); err != nil {
+		t.Fatalf("Failed to decode the compressed message: %s", err)
+	}
+	if err := decompressMessage(&received); err != nil {
+		t.Fatalf("Failed to decompress the message: %s", err)
+	}
+
+	if received.MessageType != message.MessageType || !bytes.Equal(received.Payload, message.Payload) {
+		t.Errorf("Received %s %x, expected %s %x", received.MessageType, received.Payload, message.MessageType, message.Payload)
+	}
+}
@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Routes received messages to the handler registered for their type, so that
+// receivers don't need to switch over every message type themselves.
+type Dispatcher struct {
+	handlers map[string]func(payload msgpack.RawMessage) error
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string]func(payload msgpack.RawMessage) error)}
+}
+
+// Registers the handler called with the decoded message whenever a message of
+// the given type is dispatched.
+func Register[Message any](dispatcher *Dispatcher, handler func(message Message)) {
+	messageType := reflect.TypeFor[Message]().Name()
+
+	dispatcher.handlers[messageType] = func(payload msgpack.RawMessage) error {
+		var message Message
+		if err := msgpack.Unmarshal(payload, &message); err != nil {
+			return err
+		}
+		handler(message)
+		return nil
+	}
+}
+
+// Decodes the message and calls its handler, returning a `DecodeError` if it
+// could not be decoded. Messages without a registered handler are ignored.
+func (self *Dispatcher) Dispatch(message BaseMessage) error {
+	handler, ok := self.handlers[message.MessageType]
+	if !ok {
+		return nil
+	}
+	if err := handler(message.Payload); err != nil {
+		return &DecodeError{MessageType: message.MessageType, Err: err}
+	}
+	return nil
+}
@@ -2,31 +2,71 @@ package rpc
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"io"
 	"reflect"
-	"sync"
 
 	"github.com/coder/websocket"
 	"github.com/vmihailenco/msgpack/v5"
 )
 
+// Bumped whenever the format of the messages changes, clients and servers
+// only talk to each other when they agree on it.
+const ProtocolVersion = 2
+
 type BaseMessage struct {
 	MessageType string
 	Payload     msgpack.RawMessage
+
+	// Whether the payload was compressed by `CompressMessage`, received
+	// messages are always handed out decompressed.
+	IsCompressed bool
 }
 
-var bufferPool = sync.Pool{
-	New: func() interface{} {
-		// Create a new buffer if one isn't available in the pool.
-		// This example uses a buffer with a size of 4096 bytes (4KB).
-		return make([]byte, 4096)
-	},
+// Returned when a message arrived but could not be decoded, as opposed to
+// failing to read from the connection at all.
+type DecodeError struct {
+	// Empty when not even the envelope of the message could be decoded.
+	MessageType string
+	Err         error
+}
+
+func (self *DecodeError) Error() string {
+	if self.MessageType == "" {
+		return fmt.Sprintf("failed to decode message: %s", self.Err)
+	}
+	return fmt.Sprintf("failed to decode %s: %s", self.MessageType, self.Err)
+}
+
+func (self *DecodeError) Unwrap() error {
+	return self.Err
+}
+
+// Wrapped by a `DecodeError` when a message is decoded as a different type
+// than the one it was sent as.
+var ErrUnexpectedMessage = errors.New("unexpected message type")
+
+// Returned when reading from the connection failed, usually because it was
+// closed. Nothing more can be received from it afterwards, unlike after a
+// `DecodeError`.
+type ConnectionError struct {
+	Err error
+}
+
+func (self *ConnectionError) Error() string {
+	return fmt.Sprintf("failed to read from the connection: %s", self.Err)
+}
+
+func (self *ConnectionError) Unwrap() error {
+	return self.Err
 }
 
 func NewBaseMessage(message any) BaseMessage {
+	// Only messages that cannot be encoded at all fail here, which is a bug.
 	payload, err := msgpack.Marshal(message)
 	if err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
 	encoded := BaseMessage{
 		MessageType: reflect.TypeOf(message).Name(),
@@ -36,28 +76,53 @@ func NewBaseMessage(message any) BaseMessage {
 }
 
 func WriteMessage(ctx context.Context, conn *websocket.Conn, message BaseMessage) error {
-	marshaled, err := msgpack.Marshal(message)
+	encoded, err := EncodeMessage(message)
 	if err != nil {
 		return err
 	}
-	return conn.Write(ctx, websocket.MessageBinary, marshaled)
+	return WriteEncodedMessage(ctx, conn, encoded)
 }
 
-func ReceiveMessage(ctx context.Context, conn *websocket.Conn, message *BaseMessage) error {
-	buffer := bufferPool.Get().([]byte)
-	defer bufferPool.Put(buffer)
+// A message marshaled ahead of time, so that it can be written to any number
+// of connections without marshaling it again.
+type EncodedMessage struct {
+	MessageType string
+	data        []byte
+}
+
+func EncodeMessage(message BaseMessage) (EncodedMessage, error) {
+	marshaled, err := msgpack.Marshal(message)
+	if err != nil {
+		return EncodedMessage{}, err
+	}
+	return EncodedMessage{MessageType: message.MessageType, data: marshaled}, nil
+}
+
+func WriteEncodedMessage(ctx context.Context, conn *websocket.Conn, message EncodedMessage) error {
+	return conn.Write(ctx, websocket.MessageBinary, message.data)
+}
 
+// Reads the next message in full, however many frames it was split into.
+func ReceiveMessage(ctx context.Context, conn *websocket.Conn, message *BaseMessage) error {
 	_, reader, err := conn.Reader(ctx)
 	if err != nil {
-		return err
+		return &ConnectionError{Err: err}
 	}
 
-	n, err := reader.Read(buffer)
+	data, err := io.ReadAll(reader)
 	if err != nil {
-		return err
+		return &ConnectionError{Err: err}
 	}
 
-	return msgpack.Unmarshal(buffer[:n], message)
+	if err := msgpack.Unmarshal(data, message); err != nil {
+		return &DecodeError{Err: err}
+	}
+	if message.IsCompressed {
+		if err := decompressMessage(message); err != nil {
+			return &DecodeError{MessageType: message.MessageType, Err: err}
+		}
+	}
+	return nil
 }
 
 func ReceiveExpectedMessage[ExpectedMessage any](ctx context.Context, conn *websocket.Conn, out *ExpectedMessage) error {
@@ -65,12 +130,17 @@ func ReceiveExpectedMessage[ExpectedMessage any](ctx context.Context, conn *webs
 	if err := ReceiveMessage(ctx, conn, &baseMessage); err != nil {
 		return err
 	}
-	return msgpack.Unmarshal(baseMessage.Payload, out)
+	return DecodeExpectedMessage(baseMessage, out)
 }
 
+// Decodes the payload of the message, returning a `DecodeError` if the
+// message is of another type or its payload doesn't fit the expected one.
 func DecodeExpectedMessage[ExpectedMessage any](message BaseMessage, out *ExpectedMessage) error {
+	if message.MessageType != reflect.TypeFor[ExpectedMessage]().Name() {
+		return &DecodeError{MessageType: message.MessageType, Err: ErrUnexpectedMessage}
+	}
 	if err := msgpack.Unmarshal(message.Payload, out); err != nil {
-		return err
+		return &DecodeError{MessageType: message.MessageType, Err: err}
 	}
 	return nil
 }
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestReceiveLargeMessage(t *testing.T) {
+	// Larger than what a single read of the connection returns.
+	message := NewBaseMessage(struct{ Data []byte }{bytes.Repeat([]byte("pew "), 4096)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		WriteMessage(r.Context(), conn, message)
+		conn.Read(r.Context())
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %s", err)
+	}
+	defer conn.CloseNow()
+
+	var received BaseMessage
+	if err := ReceiveMessage(ctx, conn, &received); err != nil {
+		t.Fatalf("Failed to receive the message: %s", err)
+	}
+	if received.MessageType != message.MessageType || !bytes.Equal(received.Payload, message.Payload) {
+		t.Errorf("Received %d bytes of %s, expected %d bytes of %s", len(received.Payload), received.MessageType, len(message.Payload), message.MessageType)
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const maxAsteroids = 12
+
+// Tops up the asteroids drifting across the map, replacing the ones that
+// shattered against a ship.
+func (self *Server) spawnAsteroids() {
+	count := donburi.NewQuery(filter.Contains(component.Asteroid)).Count(self.simulation.ECS.World)
+
+	for range maxAsteroids - count {
+		asteroidId := self.nextAsteroidId
+		self.nextAsteroidId++
+
+		position, radius := self.simulation.GenerateRandomAsteroid()
+		self.simulation.CreateAsteroid(asteroidId, &position, radius)
+
+		self.broadcastMessage(rpc.NewBaseMessage(messages.SpawnAsteroid{
+			Asteroid: messages.AsteroidData{
+				AsteroidId: asteroidId,
+				Position:   position,
+				Radius:     radius,
+			},
+		}))
+	}
+}
+
+func (self *Server) onAsteroidCollide(player *donburi.Entry, asteroid *donburi.Entry) {
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventAsteroidDestroyed{
+		AsteroidId: component.Asteroid.Get(asteroid).Id,
+	}))
+	self.damagePlayer(player, types.InvalidPlayerId, game.AsteroidDamage)
+}
+
+func (self *Server) getAsteroidData() []messages.AsteroidData {
+	asteroidData := []messages.AsteroidData{}
+	query := donburi.NewQuery(filter.Contains(component.Asteroid, component.Position))
+
+	for asteroid := range query.Iter(self.simulation.ECS.World) {
+		data := component.Asteroid.Get(asteroid)
+		asteroidData = append(asteroidData, messages.AsteroidData{
+			AsteroidId: data.Id,
+			Position:   *component.Position.Get(asteroid),
+			Radius:     data.Radius,
+		})
+	}
+	return asteroidData
+}
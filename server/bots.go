@@ -0,0 +1,204 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	// Bots only go after ships within this distance, and wander otherwise.
+	botSightRange = 900
+
+	// Bots stop thrusting once they are this close to their target.
+	botPreferredRange = 300
+
+	// Bots fire when their target is this close and roughly ahead of them.
+	botFireRange = 600
+	botFireAngle = 0.2
+
+	// Asteroids and walls closer than this are steered away from.
+	botAvoidRange = 250
+
+	// The chance a bot that has a shot lined up in a tick takes it.
+	botFireChance = 0.1
+
+	// How long a wandering bot keeps its heading.
+	botWanderInterval = 3 * time.Second
+
+	// Headings closer than this to the desired one count as on course.
+	botTurnTolerance = 0.1
+)
+
+// The AI driving a ship for players who want to practice alone. Bots send
+// the same moves as a client would, so the clients simulate them like any
+// other ship.
+type bot struct {
+	playerId types.PlayerId
+	held     map[types.PlayerMove]bool
+
+	wanderAngle    float64
+	nextWanderTime time.Time
+}
+
+// Creates the ship of a bot, bots have a slot in the players without a
+// connection behind it.
+func (self *Server) spawnBot(index int) {
+	playerId := self.getAvailablePlayerId()
+	self.players[playerId] = &playerConnection{isBot: true}
+
+	position := self.spawnPosition()
+	player := self.simulation.CreatePlayer(playerId, &position, fmt.Sprintf("Bot %d", index+1), true, self.assignTeam(), types.ShipFighter)
+	component.Player.Get(player).Lives = self.config.Lives
+	self.bots = append(self.bots, &bot{playerId: playerId, held: make(map[types.PlayerMove]bool)})
+}
+
+// Decides what every bot does this tick.
+func (self *Server) updateBots() {
+	if !self.hasGameStarted {
+		return
+	}
+
+	for _, bot := range self.bots {
+		// A dead ship stops moving, so the moves are held again from scratch
+		// once the bot respawns.
+		player := self.simulation.FindCorrespondingPlayer(bot.playerId)
+		if player == nil || !component.Player.Get(player).IsAlive {
+			clear(bot.held)
+			continue
+		}
+		self.updateBot(bot, player)
+	}
+}
+
+func (self *Server) updateBot(bot *bot, player *donburi.Entry) {
+	position := component.Position.Get(player)
+	target, distance := self.findBotTarget(player)
+
+	desiredAngle := bot.wander()
+	wantsThrust := true
+	wantsFire := false
+
+	if target != nil {
+		desiredAngle = angleTowards(position, target)
+		wantsThrust = distance > botPreferredRange
+		isLinedUp := math.Abs(component.AngleDifference(desiredAngle, position.Angle)) < botFireAngle
+		wantsFire = isLinedUp && distance < botFireRange && rand.Float64() < botFireChance
+	}
+
+	// Avoiding a crash matters more than chasing anyone.
+	if avoidAngle, ok := self.findBotObstacle(position); ok {
+		desiredAngle = avoidAngle
+		wantsThrust = true
+		wantsFire = false
+	}
+
+	turn := component.AngleDifference(desiredAngle, position.Angle)
+	self.holdBotMove(bot, turn > botTurnTolerance, types.PlayerStartRotateClockwise, types.PlayerStopRotateClockwise)
+	self.holdBotMove(bot, turn < -botTurnTolerance, types.PlayerStartRotateCounterClockwise, types.PlayerStopRotateCounterClockwise)
+	self.holdBotMove(bot, wantsThrust, types.PlayerStartForward, types.PlayerStopForward)
+	self.holdBotMove(bot, wantsFire, types.PlayerStartFireBullet, types.PlayerStopFireBullet)
+}
+
+// Starts or stops the move when it changes, telling the clients about it.
+func (self *Server) holdBotMove(bot *bot, isHeld bool, start types.PlayerMove, stop types.PlayerMove) {
+	if bot.held[start] == isHeld {
+		return
+	}
+	bot.held[start] = isHeld
+
+	move := stop
+	if isHeld {
+		move = start
+	}
+
+	self.simulation.RegisterPlayerMove(bot.playerId, move)
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerMove{
+		Move:     move,
+		PlayerId: bot.playerId,
+	}))
+}
+
+// Returns the closest ship within sight the bot may shoot at.
+func (self *Server) findBotTarget(player *donburi.Entry) (*component.PositionData, float64) {
+	own := component.Player.Get(player)
+	position := component.Position.Get(player)
+
+	var target *component.PositionData
+	closest := float64(botSightRange)
+
+	for other := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		data := component.Player.Get(other)
+		if data.Id == own.Id || !data.IsAlive || !data.IsConnected {
+			continue
+		}
+		if data.Team != types.TeamNone && data.Team == own.Team {
+			continue
+		}
+
+		otherPosition := component.Position.Get(other)
+		distance := math.Hypot(otherPosition.X-position.X, otherPosition.Y-position.Y)
+		if distance < closest {
+			target, closest = otherPosition, distance
+		}
+	}
+	return target, closest
+}
+
+// Returns the heading that takes the bot away from the closest asteroid or
+// wall, if one is too close.
+func (self *Server) findBotObstacle(position *component.PositionData) (float64, bool) {
+	closest := float64(botAvoidRange)
+	var awayX, awayY float64
+	found := false
+
+	for asteroid := range donburi.NewQuery(filter.Contains(component.Asteroid)).Iter(self.simulation.ECS.World) {
+		asteroidPosition := component.Position.Get(asteroid)
+		dx := position.X - asteroidPosition.X
+		dy := position.Y - asteroidPosition.Y
+
+		distance := math.Hypot(dx, dy) - component.Asteroid.Get(asteroid).Radius
+		if distance < closest {
+			closest, awayX, awayY, found = distance, dx, dy, true
+		}
+	}
+
+	// Walls only get in the way when the map doesn't wrap.
+	if !found && self.config.WorldMode == types.WorldClamp {
+		centerX := self.simulation.MapWidth/2 - position.X
+		centerY := self.simulation.MapHeight/2 - position.Y
+		nearWall := position.X < botAvoidRange || position.X > self.simulation.MapWidth-botAvoidRange ||
+			position.Y < botAvoidRange || position.Y > self.simulation.MapHeight-botAvoidRange
+		if nearWall {
+			awayX, awayY, found = centerX, centerY, true
+		}
+	}
+
+	if !found {
+		return 0, false
+	}
+	return math.Atan2(awayX, -awayY), true
+}
+
+// Returns the heading of a bot with nothing to do, changing it every now and
+// then.
+func (self *bot) wander() float64 {
+	if time.Now().After(self.nextWanderTime) {
+		self.wanderAngle = rand.Float64() * 2 * math.Pi
+		self.nextWanderTime = time.Now().Add(botWanderInterval)
+	}
+	return self.wanderAngle
+}
+
+// Returns the heading pointing from one position to the other.
+func angleTowards(from *component.PositionData, to *component.PositionData) float64 {
+	return math.Atan2(to.X-from.X, -(to.Y - from.Y))
+}
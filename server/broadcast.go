@@ -0,0 +1,126 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"context"
+	"time"
+)
+
+// How many messages may wait to be written to a player before they are
+// dropped for not keeping up.
+const outboxSize = 256
+
+// How long a write may take before the player is dropped.
+const writeTimeout = time.Second
+
+// A message encoded once for everyone it is sent to. It is only compressed
+// once sent to a player that supports it, the first time that happens.
+type broadcast struct {
+	message      rpc.BaseMessage
+	plain        rpc.EncodedMessage
+	compressed   rpc.EncodedMessage
+	isCompressed bool
+}
+
+func newBroadcast(message rpc.BaseMessage) (*broadcast, error) {
+	plain, err := rpc.EncodeMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	return &broadcast{message: message, plain: plain}, nil
+}
+
+// Returns the message encoded for a player, compressed if they support it.
+// The server mutex must be held.
+func (self *broadcast) encoding(supportsCompression bool) (rpc.EncodedMessage, error) {
+	if !supportsCompression {
+		return self.plain, nil
+	}
+
+	if !self.isCompressed {
+		self.compressed = self.plain
+		if compressedMessage := rpc.CompressMessage(self.message); compressedMessage.IsCompressed {
+			compressed, err := rpc.EncodeMessage(compressedMessage)
+			if err != nil {
+				return rpc.EncodedMessage{}, err
+			}
+			self.compressed = compressed
+		}
+		self.isCompressed = true
+	}
+	return self.compressed, nil
+}
+
+// Sends the message to every player.
+func (self *Server) broadcastMessage(message rpc.BaseMessage) {
+	self.broadcastMessageExcept(types.InvalidPlayerId, message)
+}
+
+// Sends the message to every player but the excluded one.
+func (self *Server) broadcastMessageExcept(except types.PlayerId, message rpc.BaseMessage) {
+	encoded, err := newBroadcast(message)
+	if err != nil {
+		self.logger.Error("Failed to encode a message", "type", message.MessageType, "err", err)
+		return
+	}
+
+	for playerId, playerConn := range self.players {
+		if playerId != except {
+			self.sendBroadcast(playerId, playerConn, encoded)
+		}
+	}
+}
+
+func (self *Server) sendMessage(playerId types.PlayerId, playerConn *playerConnection, message rpc.BaseMessage) {
+	encoded, err := newBroadcast(message)
+	if err != nil {
+		self.logger.Error("Failed to encode a message", "type", message.MessageType, "err", err)
+		return
+	}
+	self.sendBroadcast(playerId, playerConn, encoded)
+}
+
+// Queues the message for the writer of the player, the server mutex must be
+// held. A player that doesn't keep up with their messages is dropped by
+// closing their connection, which cleans them up the same way as a regular
+// disconnection.
+func (self *Server) sendBroadcast(playerId types.PlayerId, playerConn *playerConnection, message *broadcast) {
+	if !playerConn.isConnected {
+		return
+	}
+
+	encoded, err := message.encoding(playerConn.supportsCompression)
+	if err != nil {
+		self.logger.Error("Failed to encode a message", "type", message.message.MessageType, "err", err)
+		return
+	}
+
+	select {
+	case playerConn.outbox <- encoded:
+	default:
+		self.logger.Warn("Too many messages queued, dropping the player", "player", playerId, "type", encoded.MessageType)
+		playerConn.conn.CloseNow()
+	}
+}
+
+// Writes the messages queued for the player one after the other, so that
+// they arrive in the order they were sent, until the connection ends.
+func (self *Server) writeMessages(ctx context.Context, playerId types.PlayerId, playerConn *playerConnection) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case encoded := <-playerConn.outbox:
+			writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			err := rpc.WriteEncodedMessage(writeCtx, playerConn.conn, encoded)
+			cancel()
+
+			if err != nil {
+				self.logger.Warn("Failed to send a message, dropping the player", "player", playerId, "type", encoded.MessageType, "err", err)
+				playerConn.conn.CloseNow()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"astro-blasters/game"
+	"astro-blasters/game/types"
+	"log/slog"
+	"time"
+)
+
+type ServerConfig struct {
+	// Where the server reports connections, errors and the state of the
+	// game, its level decides how verbose the server is.
+	Logger *slog.Logger
+
+	// The stats of every weapon, nil for the defaults. Shots fired before
+	// the cooldown of the weapon are dropped.
+	Weapons map[types.WeaponId]game.WeaponStats
+
+	// The cooldown used instead while the player has the rapid fire power-up.
+	RapidFireCooldown time.Duration
+
+	// Splits the players into two teams that cannot damage each other.
+	TeamMode bool
+
+	// The most ships allowed in the game at once, players connecting past it
+	// are turned away. Spectators don't count, and 0 means no limit.
+	MaxPlayers int
+
+	// The number of ships flown by the server, for practicing alone.
+	Bots int
+
+	// How many times per second the server advances the simulation. Clients
+	// render at their own frame rate and scale the simulation by the elapsed
+	// time, so the tick rate only changes how often collisions are resolved.
+	TickRate int
+
+	// How long a match lasts once the game started, 0 lets it go on forever.
+	MatchDuration time.Duration
+
+	// How long after a match the next round starts in the same arena, 0
+	// sends the players back to the lobby instead.
+	RoundBreak time.Duration
+
+	// Whether the scores carry over from one round to the next.
+	KeepScores bool
+
+	// How long ships cannot be damaged after spawning, unless they fire.
+	RespawnProtection time.Duration
+
+	// How many times a ship can die before it is out of the match, the last
+	// ship left wins. 0 gives everyone unlimited lives.
+	Lives int
+
+	// Where the all-time stats of the players are saved, an empty path keeps
+	// them in memory only.
+	LeaderboardPath string
+
+	// Where ships are placed when they join or respawn.
+	SpawnStrategy SpawnStrategy
+
+	// Whether ships and bullets stop at the edges of the map or wrap around
+	// to the other side.
+	WorldMode types.WorldMode
+
+	// The size of the map in pixels, sent to the clients when they connect.
+	MapWidth  float64
+	MapHeight float64
+
+	// The certificate and key the server uses to serve over HTTPS and WSS,
+	// both empty to serve plain HTTP and WS.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+func (self *ServerConfig) IsSecure() bool {
+	return self.TLSCertFile != ""
+}
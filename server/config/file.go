@@ -0,0 +1,169 @@
+package config
+
+import (
+	"astro-blasters/game"
+	"astro-blasters/game/types"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// The smallest map the ships, spawn points and asteroids fit on.
+const minMapSize = 512
+
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Logger:            slog.Default(),
+		RapidFireCooldown: 100 * time.Millisecond,
+		MaxPlayers:        16,
+		TickRate:          60,
+		MatchDuration:     5 * time.Minute,
+		RespawnProtection: 2 * time.Second,
+		LeaderboardPath:   "leaderboard.json",
+		SpawnStrategy:     SpawnFarthest,
+		WorldMode:         types.WorldClamp,
+		MapWidth:          game.DefaultMapWidth,
+		MapHeight:         game.DefaultMapHeight,
+	}
+}
+
+// The settings as written in a config file, the ones left out keep their
+// current value. Durations are given as strings such as "5m".
+type serverFile struct {
+	Weapons           *string
+	RapidFireCooldown *string
+	TeamMode          *bool
+	MaxPlayers        *int
+	Bots              *int
+	TickRate          *int
+	MatchDuration     *string
+	RoundBreak        *string
+	KeepScores        *bool
+	RespawnProtection *string
+	Lives             *int
+	LeaderboardPath   *string
+	SpawnStrategy     *string
+	Wrap              *bool
+	MapWidth          *float64
+	MapHeight         *float64
+	TLSCertFile       *string
+	TLSKeyFile        *string
+}
+
+// Reads the settings from a JSON file on top of the config, e.g.
+// `{"TickRate": 30, "MatchDuration": "10m"}`. The weapons are read from the
+// file the config points to.
+func LoadServerConfig(path string, config *ServerConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read the config from %s: %w", path, err)
+	}
+
+	var file serverFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("Failed to parse the config from %s: %w", path, err)
+	}
+
+	set(&config.TeamMode, file.TeamMode)
+	set(&config.MaxPlayers, file.MaxPlayers)
+	set(&config.Bots, file.Bots)
+	set(&config.TickRate, file.TickRate)
+	set(&config.KeepScores, file.KeepScores)
+	set(&config.Lives, file.Lives)
+	set(&config.LeaderboardPath, file.LeaderboardPath)
+	set(&config.MapWidth, file.MapWidth)
+	set(&config.MapHeight, file.MapHeight)
+	set(&config.TLSCertFile, file.TLSCertFile)
+	set(&config.TLSKeyFile, file.TLSKeyFile)
+
+	if err := setDuration(&config.RapidFireCooldown, file.RapidFireCooldown, "RapidFireCooldown", path); err != nil {
+		return err
+	}
+	if err := setDuration(&config.MatchDuration, file.MatchDuration, "MatchDuration", path); err != nil {
+		return err
+	}
+	if err := setDuration(&config.RoundBreak, file.RoundBreak, "RoundBreak", path); err != nil {
+		return err
+	}
+	if err := setDuration(&config.RespawnProtection, file.RespawnProtection, "RespawnProtection", path); err != nil {
+		return err
+	}
+
+	if file.SpawnStrategy != nil {
+		strategy, err := ParseSpawnStrategy(*file.SpawnStrategy)
+		if err != nil {
+			return fmt.Errorf("Invalid SpawnStrategy in %s: %w", path, err)
+		}
+		config.SpawnStrategy = strategy
+	}
+	if file.Wrap != nil {
+		config.WorldMode = types.WorldClamp
+		if *file.Wrap {
+			config.WorldMode = types.WorldWrap
+		}
+	}
+	if file.Weapons != nil {
+		weapons, err := game.LoadWeapons(*file.Weapons)
+		if err != nil {
+			return err
+		}
+		config.Weapons = weapons
+	}
+	return nil
+}
+
+// Returns why the config cannot be used, if it can't.
+func (self *ServerConfig) Validate() error {
+	if self.TickRate < 1 || self.TickRate > 1000 {
+		return fmt.Errorf("Invalid tick rate %d, it should be from 1 to 1000", self.TickRate)
+	}
+	if self.MaxPlayers < 0 {
+		return fmt.Errorf("Invalid player limit %d, it should be 0 or more", self.MaxPlayers)
+	}
+	if self.Bots < 0 {
+		return fmt.Errorf("Invalid number of bots %d, it should be 0 or more", self.Bots)
+	}
+	if self.Lives < 0 {
+		return fmt.Errorf("Invalid number of lives %d, it should be 0 or more", self.Lives)
+	}
+	if self.MatchDuration < 0 {
+		return fmt.Errorf("Invalid match duration %s, it should be 0 or more", self.MatchDuration)
+	}
+	if self.RoundBreak < 0 {
+		return fmt.Errorf("Invalid round break %s, it should be 0 or more", self.RoundBreak)
+	}
+	if self.RespawnProtection < 0 {
+		return fmt.Errorf("Invalid respawn protection %s, it should be 0 or more", self.RespawnProtection)
+	}
+	if self.RapidFireCooldown < 0 {
+		return fmt.Errorf("Invalid rapid fire cooldown %s, it should be 0 or more", self.RapidFireCooldown)
+	}
+	if self.MapWidth < minMapSize || self.MapHeight < minMapSize {
+		return fmt.Errorf("Invalid map size %gx%g, it should be at least %dx%d", self.MapWidth, self.MapHeight, minMapSize, minMapSize)
+	}
+	if (self.TLSCertFile == "") != (self.TLSKeyFile == "") {
+		return fmt.Errorf("Invalid TLS settings, both a certificate and a key are needed to serve over TLS")
+	}
+	return nil
+}
+
+// Overwrites the value with the one from the file, if it was given.
+func set[T any](value *T, loaded *T) {
+	if loaded != nil {
+		*value = *loaded
+	}
+}
+
+func setDuration(value *time.Duration, loaded *string, name string, path string) error {
+	if loaded == nil {
+		return nil
+	}
+	duration, err := time.ParseDuration(*loaded)
+	if err != nil {
+		return fmt.Errorf("Invalid %s in %s: %w", name, path, err)
+	}
+	*value = duration
+	return nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+// Where ships are placed when they join the game or respawn.
+type SpawnStrategy int
+
+const (
+	// Anywhere on the map.
+	SpawnRandom SpawnStrategy = iota
+	// The spot furthest from every other ship out of a few random ones.
+	SpawnFarthest
+	// The spawn point around the edges of the map furthest from every other
+	// ship.
+	SpawnFixedPoints
+)
+
+func ParseSpawnStrategy(name string) (SpawnStrategy, error) {
+	switch name {
+	case "random":
+		return SpawnRandom, nil
+	case "farthest":
+		return SpawnFarthest, nil
+	case "fixed":
+		return SpawnFixedPoints, nil
+	}
+	return SpawnRandom, fmt.Errorf("Invalid spawn strategy %q", name)
+}
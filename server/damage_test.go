@@ -0,0 +1,39 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/server/config"
+	"testing"
+)
+
+func TestShipsOnlyDieOnce(t *testing.T) {
+	server := newTestServer(func(serverConfig *config.ServerConfig) { serverConfig.Lives = 3 })
+	killer := addTestPlayer(server, 0, component.PositionData{X: 100, Y: 100})
+	victim := addTestPlayer(server, 1, component.PositionData{X: 100, Y: 100})
+	component.Player.Get(victim).Lives = 3
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	// Two lethal hits landing in the same tick.
+	server.damagePlayer(victim, 0, 1000)
+	server.damagePlayer(victim, 0, 1000)
+
+	victimData := component.Player.Get(victim)
+	if victimData.Health != 0 || victimData.IsAlive {
+		t.Errorf("The victim has %g health and is alive: %t", victimData.Health, victimData.IsAlive)
+	}
+	if victimData.Lives != 2 {
+		t.Errorf("The victim has %d lives left, expected 2", victimData.Lives)
+	}
+	if score := component.Player.Get(killer).Score; score != 10 {
+		t.Errorf("The killer scored %d, expected one kill worth 10", score)
+	}
+
+	server.leaderboard.mutex.Lock()
+	deaths := server.leaderboard.get(victimData.Name).Deaths
+	server.leaderboard.mutex.Unlock()
+	if deaths != 1 {
+		t.Errorf("The leaderboard counted %d deaths, expected 1", deaths)
+	}
+}
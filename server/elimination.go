@@ -0,0 +1,89 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Players only have so many lives in an elimination game, the last one left
+// wins the match.
+func (self *Server) isElimination() bool {
+	return self.config.Lives > 0
+}
+
+// Gives every ship its lives back for a new match, bringing back the ones
+// eliminated during the last one.
+func (self *Server) resetLives() {
+	if !self.isElimination() {
+		return
+	}
+
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		playerData.Lives = self.config.Lives
+		if playerData.IsAlive || !playerData.IsConnected {
+			continue
+		}
+
+		position := self.spawnPosition()
+		self.simulation.RespawnPlayer(player, position)
+		self.protect(player)
+		self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerRespawned{
+			PlayerId: playerData.Id,
+			Position: position,
+		}))
+	}
+}
+
+// Takes a life from the player that just died, returning whether it was
+// their last one.
+func (self *Server) loseLife(player *donburi.Entry) bool {
+	if !self.isElimination() {
+		return false
+	}
+
+	playerData := component.Player.Get(player)
+	playerData.Lives = max(playerData.Lives-1, 0)
+	return playerData.Lives == 0
+}
+
+// Ends the match once a single ship is left, as long as someone was actually
+// eliminated so that a lone player can still practice.
+func (self *Server) updateElimination() {
+	if !self.isElimination() || !self.hasGameStarted || self.isBetweenRounds() {
+		return
+	}
+
+	survivors, eliminated := 0, 0
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		if !playerData.IsConnected {
+			continue
+		}
+		if playerData.Lives > 0 {
+			survivors++
+		} else {
+			eliminated++
+		}
+	}
+
+	if eliminated > 0 && survivors <= 1 {
+		self.logger.Info("Last player standing", "eliminated", eliminated)
+		self.endMatch()
+	}
+}
+
+// Returns the names of the players that still have lives left.
+func survivorNames(players []messages.PlayerData) []string {
+	names := []string{}
+	for _, player := range players {
+		if player.Lives > 0 {
+			names = append(names, player.PlayerName)
+		}
+	}
+	return names
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"time"
+)
+
+// Clients ping the server every second, the ones we don't hear from for this
+// long are considered gone.
+const heartbeatTimeout = 10 * time.Second
+
+func (self *playerConnection) markSeen() {
+	self.lastSeen.Store(time.Now().UnixNano())
+}
+
+// Closes the connections that went silent, which cleans up their players the
+// same way as a regular disconnection.
+func (self *Server) dropSilentPlayers() {
+	for playerId, playerConn := range self.players {
+		if !playerConn.isConnected {
+			continue
+		}
+
+		lastSeen := time.Unix(0, playerConn.lastSeen.Load())
+		if time.Since(lastSeen) > heartbeatTimeout {
+			self.logger.Info("Dropping a silent player", "player", playerId, "timeout", heartbeatTimeout)
+			playerConn.conn.CloseNow()
+		}
+	}
+}
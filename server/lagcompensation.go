@@ -0,0 +1,116 @@
+package server
+
+import (
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"time"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	// Enough samples to cover the longest rewind at the highest tick rates.
+	positionHistorySize = 64
+
+	// Shooters lagging further behind are only compensated up to this much,
+	// otherwise their victims get shot long after they took cover.
+	maxRewind = 200 * time.Millisecond
+
+	// How far a ship may have moved over the longest rewind, used to widen
+	// the search for the ships a bullet may hit.
+	maxRewindDistance = 200
+)
+
+type positionSample struct {
+	at       time.Time
+	position component.PositionData
+}
+
+// A ring buffer of where a ship was over the last few ticks.
+type positionHistory struct {
+	samples [positionHistorySize]positionSample
+	next    int
+	count   int
+}
+
+func (self *positionHistory) record(at time.Time, position component.PositionData) {
+	self.samples[self.next] = positionSample{at: at, position: position}
+	self.next = (self.next + 1) % positionHistorySize
+	self.count = min(self.count+1, positionHistorySize)
+}
+
+// Returns where the ship was at the given time, interpolating between the two
+// samples around it. Times older than the history get the oldest sample. In a
+// wrapping map a ship that crossed an edge moves the short way around, the
+// position may then lie just past the edge.
+func (self *positionHistory) at(at time.Time, simulation *game.GameSimulation) (component.PositionData, bool) {
+	if self.count == 0 {
+		return component.PositionData{}, false
+	}
+
+	// Walk back from the newest sample until we pass the requested time.
+	newer := self.samples[(self.next-1+positionHistorySize)%positionHistorySize]
+	for i := 2; i <= self.count; i++ {
+		older := self.samples[(self.next-i+positionHistorySize)%positionHistorySize]
+		if !older.at.After(at) {
+			span := newer.at.Sub(older.at)
+			if span <= 0 {
+				return older.position, true
+			}
+
+			t := float64(at.Sub(older.at)) / float64(span)
+			dx := newer.position.X - older.position.X
+			dy := newer.position.Y - older.position.Y
+			if simulation.WorldMode == types.WorldWrap {
+				dx = game.WrapDelta(dx, simulation.MapWidth)
+				dy = game.WrapDelta(dy, simulation.MapHeight)
+			}
+
+			position := older.position
+			position.X += dx * t
+			position.Y += dy * t
+			return position, true
+		}
+		newer = older
+	}
+	return newer.position, true
+}
+
+// Remembers where every ship is after the tick.
+func (self *Server) recordPositions(now time.Time) {
+	for player := range donburi.NewQuery(filter.Contains(component.Player, component.Position)).Iter(self.simulation.ECS.World) {
+		connection, ok := self.players[component.Player.Get(player).Id]
+		if ok {
+			connection.history.record(now, *component.Position.Get(player))
+		}
+	}
+}
+
+// Returns where the victim was on the screen of the shooter, rewinding it by
+// the round trip time the shooter reported so that hits are resolved the way
+// the shooter saw them.
+func (self *Server) rewindPlayer(victim *donburi.Entry, shooterId types.PlayerId) *component.PositionData {
+	current := component.Position.Get(victim)
+
+	shooter, ok := self.players[shooterId]
+	if !ok {
+		return current
+	}
+	connection, ok := self.players[component.Player.Get(victim).Id]
+	if !ok {
+		return current
+	}
+
+	rewind := min(time.Duration(shooter.roundTripTime.Load()), maxRewind)
+	if rewind <= 0 {
+		return current
+	}
+
+	position, ok := connection.history.at(time.Now().Add(-rewind), self.simulation)
+	if !ok {
+		return current
+	}
+	return &position
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRewindAcrossWrappedEdge(t *testing.T) {
+	simulation := game.NewGameSimulation()
+	start := time.Now()
+
+	// The ship flew out through the right edge and came back on the left.
+	var history positionHistory
+	history.record(start, component.PositionData{X: simulation.MapWidth - 10, Y: 100})
+	history.record(start.Add(100*time.Millisecond), component.PositionData{X: 10, Y: 100})
+
+	halfway := start.Add(50 * time.Millisecond)
+	tests := []struct {
+		wraps    bool
+		expected float64
+	}{
+		{wraps: true, expected: simulation.MapWidth},
+		{wraps: false, expected: simulation.MapWidth / 2},
+	}
+	for _, test := range tests {
+		simulation.WorldMode = types.WorldClamp
+		if test.wraps {
+			simulation.WorldMode = types.WorldWrap
+		}
+
+		position, ok := history.at(halfway, simulation)
+		if !ok {
+			t.Fatal("The history is empty")
+		}
+		if math.Abs(position.X-test.expected) > 1e-6 {
+			t.Errorf("Halfway with wrapping %t the ship was at %g, expected %g", test.wraps, position.X, test.expected)
+		}
+	}
+}
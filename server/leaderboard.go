@@ -0,0 +1,207 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/coder/websocket"
+)
+
+// The number of players sent to clients asking for the leaderboard.
+const leaderboardSize = 10
+
+// The all-time stats of every player, keyed by their name.
+type leaderboard struct {
+	// Guards the stats since kills are recorded from the connection
+	// goroutines while the match timer saves them.
+	mutex sync.Mutex
+	path  string
+	stats map[string]*messages.PlayerStats
+
+	// Saves are written in the background, one at a time. A save that was
+	// overtaken by a later one is skipped instead of replacing it.
+	fileMutex   sync.Mutex
+	lastSave    int
+	lastWritten int
+}
+
+// Loads the stats saved at the path, an empty path keeps them in memory only.
+func loadLeaderboard(path string, logger *slog.Logger) *leaderboard {
+	self := &leaderboard{path: path, stats: make(map[string]*messages.PlayerStats)}
+	if path == "" {
+		return self
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return self
+	}
+	if err != nil {
+		logger.Error("Failed to read the leaderboard", "path", path, "err", err)
+		return self
+	}
+
+	if err := json.Unmarshal(data, &self.stats); err != nil {
+		logger.Error("Failed to parse the leaderboard", "path", path, "err", err)
+	}
+	return self
+}
+
+// Returns the stats of the player, creating them on the first visit. The
+// mutex must be held.
+func (self *leaderboard) get(playerName string) *messages.PlayerStats {
+	stats, ok := self.stats[playerName]
+	if !ok {
+		stats = &messages.PlayerStats{PlayerName: playerName}
+		self.stats[playerName] = stats
+	}
+	return stats
+}
+
+// Counts a death for the victim and a kill for the killer, if there is one.
+func (self *leaderboard) recordKill(killer string, victim string) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.get(victim).Deaths++
+	if killer != "" {
+		self.get(killer).Kills++
+	}
+}
+
+func (self *leaderboard) recordWin(playerName string) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.get(playerName).Wins++
+}
+
+// Writes the stats to disk in the background, so that the match doesn't wait
+// on the disk.
+func (self *leaderboard) save(logger *slog.Logger) {
+	if self.path == "" {
+		return
+	}
+
+	self.mutex.Lock()
+	stats := make(map[string]messages.PlayerStats, len(self.stats))
+	for playerName, playerStats := range self.stats {
+		stats[playerName] = *playerStats
+	}
+	self.lastSave++
+	save := self.lastSave
+	self.mutex.Unlock()
+
+	go func() {
+		if err := self.write(save, stats); err != nil {
+			logger.Error("Failed to save the leaderboard", "path", self.path, "err", err)
+		}
+	}()
+}
+
+// Replaces the previous file at once so that a crash never leaves half of it
+// behind.
+func (self *leaderboard) write(save int, stats map[string]messages.PlayerStats) error {
+	self.fileMutex.Lock()
+	defer self.fileMutex.Unlock()
+
+	if save < self.lastWritten {
+		return nil
+	}
+	self.lastWritten = save
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temporary := self.path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temporary, self.path)
+}
+
+// Returns the best players, ranked by their wins and then by their kills.
+func (self *leaderboard) top(count int) []messages.PlayerStats {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	entries := []messages.PlayerStats{}
+	for _, stats := range self.stats {
+		entries = append(entries, *stats)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Wins != entries[j].Wins {
+			return entries[i].Wins > entries[j].Wins
+		}
+		if entries[i].Kills != entries[j].Kills {
+			return entries[i].Kills > entries[j].Kills
+		}
+		return entries[i].PlayerName < entries[j].PlayerName
+	})
+
+	return entries[:min(count, len(entries))]
+}
+
+// Answers a client that only wants to see the leaderboard, then hangs up.
+func (self *Server) sendLeaderboard(ctx context.Context, connection *websocket.Conn) error {
+	err := rpc.WriteMessage(ctx, connection, rpc.NewBaseMessage(messages.LeaderboardData{
+		Entries: self.leaderboard.top(leaderboardSize),
+	}))
+	connection.Close(websocket.StatusNormalClosure, "sent the leaderboard")
+	return err
+}
+
+// Returns the names of the players who won the match, the whole winning team
+// in the team mode or the best player otherwise. Nobody wins a tie.
+func matchWinners(players []messages.PlayerData) []string {
+	teamScores := map[types.Team]int{}
+	for _, player := range players {
+		if player.Team != types.TeamNone {
+			teamScores[player.Team] += player.Score
+		}
+	}
+
+	winners := []string{}
+	if len(teamScores) > 0 {
+		winningTeam := types.TeamRed
+		if teamScores[types.TeamBlue] > teamScores[types.TeamRed] {
+			winningTeam = types.TeamBlue
+		} else if teamScores[types.TeamBlue] == teamScores[types.TeamRed] {
+			return winners
+		}
+
+		for _, player := range players {
+			if player.Team == winningTeam {
+				winners = append(winners, player.PlayerName)
+			}
+		}
+		return winners
+	}
+
+	var best *messages.PlayerData
+	isTied := false
+	for i, player := range players {
+		if best == nil || player.Score > best.Score {
+			best = &players[i]
+			isTied = false
+		} else if player.Score == best.Score {
+			isTied = true
+		}
+	}
+
+	if best != nil && !isTied {
+		winners = append(winners, best.PlayerName)
+	}
+	return winners
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"time"
+)
+
+// How long the lobby waits for the remaining players once someone is ready.
+const lobbyCountdown = 30 * time.Second
+
+func (self *Server) registerPlayerReady(playerId types.PlayerId, isReady bool) {
+	if self.hasGameStarted || self.players[playerId].isSpectator {
+		return
+	}
+
+	self.players[playerId].isReady = isReady
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerReady{
+		PlayerId: playerId,
+		IsReady:  isReady,
+	}))
+
+	if isReady && self.lobbyTimer == nil {
+		self.lobbyTimer = time.AfterFunc(lobbyCountdown, func() {
+			self.mutex.Lock()
+			defer self.mutex.Unlock()
+			self.startGame()
+		})
+	}
+
+	self.startGameIfEveryoneIsReady()
+}
+
+// Starts the game as soon as every connected player is ready.
+func (self *Server) startGameIfEveryoneIsReady() {
+	if self.hasGameStarted {
+		return
+	}
+
+	connectedPlayers := 0
+	for _, connection := range self.players {
+		if !connection.isConnected || connection.isSpectator {
+			continue
+		}
+		if !connection.isReady {
+			return
+		}
+		connectedPlayers += 1
+	}
+
+	if connectedPlayers > 0 {
+		self.startGame()
+	}
+}
+
+func (self *Server) startGame() {
+	if self.hasGameStarted {
+		return
+	}
+
+	if self.lobbyTimer != nil {
+		self.lobbyTimer.Stop()
+		self.lobbyTimer = nil
+	}
+
+	self.hasGameStarted = true
+	self.resetLives()
+	self.logger.Info("Game started", "duration", self.config.MatchDuration)
+	if self.config.MatchDuration > 0 {
+		self.matchEndsWhen = time.Now().Add(self.config.MatchDuration)
+	}
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventGameStart{
+		PlayerData: self.getPlayerData(),
+	}))
+}
+
+func (self *Server) isPlayerReady(playerId types.PlayerId) bool {
+	connection, ok := self.players[playerId]
+	return ok && connection.isReady
+}
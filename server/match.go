@@ -0,0 +1,54 @@
+package server
+
+import (
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"time"
+)
+
+// Tells the players how long the match has left, and ends it once the time
+// is up.
+func (self *Server) updateMatchTimer() {
+	if !self.hasGameStarted || self.matchEndsWhen.IsZero() || self.isPaused || self.isBetweenRounds() {
+		return
+	}
+
+	remaining := time.Until(self.matchEndsWhen)
+	if remaining > 0 {
+		self.broadcastMessage(rpc.NewBaseMessage(messages.MatchTimer{Remaining: remaining}))
+		return
+	}
+
+	self.endMatch()
+}
+
+// Sends the final scores and sends the players back to the lobby, where they
+// have to ready up again for the next match.
+func (self *Server) endMatch() {
+	players := self.getPlayerData()
+	self.broadcastMessage(rpc.NewBaseMessage(messages.MatchEnded{
+		PlayerData:  players,
+		NextRoundIn: self.config.RoundBreak,
+	}))
+
+	winners := matchWinners(players)
+	if self.isElimination() {
+		winners = survivorNames(players)
+	}
+	for _, winner := range winners {
+		self.leaderboard.recordWin(winner)
+	}
+	self.leaderboard.save(self.logger)
+
+	self.logger.Info("Match ended", "players", len(players))
+	self.matchEndsWhen = time.Time{}
+	if self.config.RoundBreak > 0 {
+		self.scheduleNextRound()
+		return
+	}
+
+	self.hasGameStarted = false
+	for _, connection := range self.players {
+		connection.isReady = false
+	}
+}
@@ -1,29 +1,142 @@
 package messages
 
 import (
+	"astro-blasters/game"
 	"astro-blasters/game/component"
 	"astro-blasters/game/types"
+	"time"
 )
 
 type PlayerData struct {
 	PlayerId    types.PlayerId
 	PlayerName  string
+	Team        types.Team
+	Class       types.ShipClass
+	Weapon      types.WeaponId
 	Position    component.PositionData
+	Health      float64
+	Shield      float64
+	Score       int
+	Lives       int
+	IsAlive     bool
 	IsConnected bool
+	IsReady     bool
+
+	// Whether the ship just spawned and cannot be damaged yet.
+	IsInvulnerable bool
+
+	// The sequence of the last move the server processed from this player.
+	LastSequence uint32
+}
+
+type AsteroidData struct {
+	AsteroidId types.AsteroidId
+	Position   component.PositionData
+	Radius     float64
+}
+
+// Missiles are sent with the snapshots since they steer, and would otherwise
+// drift apart between the clients.
+type MissileData struct {
+	MissileId types.MissileId
+	Position  component.PositionData
+	TargetId  types.PlayerId
+}
+
+type PowerUpData struct {
+	PowerUpId types.PowerUpId
+	Kind      types.PowerUpKind
+	Position  component.PositionData
 }
 
 type ConnectionHandshake struct {
+	// Must match `rpc.ProtocolVersion` on the server.
+	ProtocolVersion int
+
 	PlayerName string
+
+	// Spectators watch the game without being given a ship.
+	IsSpectator bool
+
+	ShipClass types.ShipClass
+
+	// Whether the client can read compressed messages, large messages such as
+	// snapshots are then compressed before being sent.
+	SupportsCompression bool
+
+	// Sent back by players that lost their connection, so that they take
+	// their ship back instead of joining with a new one.
+	ReconnectToken string
 }
 
+// Message sent from the server instead of the handshake response when it
+// refuses the connection.
+type ConnectionRejected struct {
+	Reason string
+}
+
+// Message sent from the server instead of the handshake response when every
+// ship is taken, spectators are still let in.
+type ServerFull struct {
+	MaxPlayers int
+}
+
+// Message sent from a player to the server to give up their ship and watch
+// the rest of the game, such as after dying. Players watching from the start
+// set `IsSpectator` in the handshake instead.
+type JoinAsSpectator struct{}
+
 type ConnectionHandshakeResponse struct {
 	PlayerId   types.PlayerId
 	PlayerData []PlayerData
+
+	// Players joining a game that already started skip the lobby.
+	HasGameStarted bool
+
+	// Clients have to simulate the edges of the map the same way the server
+	// does.
+	WorldMode types.WorldMode
+
+	// The size of the map, 0 in replays recorded before servers could pick
+	// it.
+	MapWidth  float64
+	MapHeight float64
+
+	// The stats of every weapon, so that clients fire bullets as fast as the
+	// server does.
+	Weapons map[types.WeaponId]game.WeaponStats
+
+	// The lives every player starts a match with, 0 when they are unlimited.
+	Lives int
+
+	// Presented in the handshake to take the ship back after losing the
+	// connection, for a while. Each connection is given a new one.
+	ReconnectToken string
+}
+
+// Message periodically sent from the server to the clients containing the
+// state of every connected player, so that clients can recover from any
+// drift in their own simulation.
+type WorldSnapshot struct {
+	// Acknowledged by the client so that later snapshots can be sent as a
+	// `WorldSnapshotDelta`.
+	Sequence uint32
+
+	Players   []PlayerData
+	Asteroids []AsteroidData
+	PowerUps  []PowerUpData
+	Missiles  []MissileData
 }
 
+// Message sent from the server to the clients when a player's reported
+// position does not match the server's simulation.
 type UpdatePosition struct {
 	PlayerId types.PlayerId
 	Position component.PositionData
+
+	// The sequence of the move the position was checked against, so that the
+	// player can re-apply what it predicted since then.
+	Sequence uint32
 }
 
 // Message sent from the client to the server to tell the
@@ -34,6 +147,9 @@ type RegisterPlayerMove struct {
 	// We send the position to see if it matches how the server moved
 	// the player.
 	Position component.PositionData
+
+	// Increases with every move sent by the client.
+	Sequence uint32
 }
 
 // Message sent from the server to the clients to render the
@@ -43,11 +159,29 @@ type EventPlayerMove struct {
 	PlayerId types.PlayerId
 }
 
+// Message sent from the client to the server when the player steers with an
+// analog stick, sent again whenever the heading changes noticeably.
+type UpdateSteering struct {
+	IsSteering bool
+
+	// The heading the ship turns towards, in radians.
+	Angle float64
+}
+
+// Message sent from the server to the clients when another player steers.
+type EventPlayerSteering struct {
+	PlayerId   types.PlayerId
+	IsSteering bool
+	Angle      float64
+}
+
 // Message sent from the server to the clients to render the
 // following position of the new player.
 type EventPlayerConnected struct {
 	PlayerId   types.PlayerId
 	PlayerName string
+	Team       types.Team
+	Class      types.ShipClass
 	Position   component.PositionData
 }
 
@@ -59,19 +193,166 @@ type EventPlayerDisconnected struct {
 
 type EventPlayerFireBullet struct {
 	PlayerId types.PlayerId
+	Angle    float64 // The direction the bullets were fired in
+
+	// Set when the bullets are missiles that home in on `TargetId`.
+	TargetId  types.PlayerId
+	MissileId types.MissileId
 }
 
-type EventUpdateHealth struct {
-	PlayerId types.PlayerId // The player whose health is being updated
-	Health   float64        // The updated health value of the player
+// Message sent from the client to the server when the player aims somewhere
+// else, so that their bullets leave along that angle instead of the heading
+// of the ship.
+type UpdateAim struct {
+	IsAiming bool
+	Angle    float64
+}
+
+// Message sent from the server to the clients when a player gets hit, a hit
+// absorbed by a shield leaves the health untouched but breaks the shield.
+type EventPlayerHit struct {
+	PlayerId   types.PlayerId // The player who got hit
+	AttackerId types.PlayerId // The player who fired the bullet, invalid for asteroids
+	Health     float64        // The updated health value of the player
+	Shield     float64        // The updated shield value of the player
 }
 
 type EventPlayerDied struct {
 	PlayerId types.PlayerId // The player whose health is being updated
 	KilledBy types.PlayerId
+
+	// The lives the player has left in an elimination game, they are out
+	// once it reaches 0.
+	Lives int
 }
 
 type EventPlayerRespawned struct {
 	PlayerId types.PlayerId
 	Position component.PositionData
 }
+
+// Message sent from the client to the server with a chat message typed by
+// the player.
+type ChatMessage struct {
+	Text string
+}
+
+// Message sent from the server to the clients to display a chat message.
+type EventChatMessage struct {
+	PlayerId types.PlayerId
+	Text     string
+}
+
+// Message sent from the client to the server while in the lobby to tell
+// whether the player is ready to play.
+type RegisterPlayerReady struct {
+	IsReady bool
+}
+
+// Message sent from the server to the clients in the lobby when a player
+// changed their ready status.
+type EventPlayerReady struct {
+	PlayerId types.PlayerId
+	IsReady  bool
+}
+
+// Message sent from the server to the clients in the lobby once the game
+// starts, along with the state of every player.
+type EventGameStart struct {
+	PlayerData []PlayerData
+}
+
+// Message periodically sent from the client to the server to measure the
+// round trip time.
+type Ping struct {
+	// When the client sent the ping, in unix nanoseconds.
+	SentAt int64
+
+	// The round trip time the client measured with the previous ping, the
+	// server uses it to rewind the ships to what the player saw.
+	RoundTripTime time.Duration
+}
+
+// Message sent from the server back to the client that sent the ping.
+type Pong struct {
+	SentAt int64
+}
+
+// Message sent from the server to the clients when a new asteroid starts
+// drifting across the map.
+type SpawnAsteroid struct {
+	Asteroid AsteroidData
+}
+
+// Message sent from the server to the clients when an asteroid shattered
+// against a ship.
+type EventAsteroidDestroyed struct {
+	AsteroidId types.AsteroidId
+}
+
+// Message sent from the server to the clients when a power-up appears on the
+// map.
+type SpawnPowerUp struct {
+	PowerUp PowerUpData
+}
+
+// Message sent from the server to the clients when a player picked up a
+// power-up and was granted its effect.
+type PowerUpCollected struct {
+	PowerUpId types.PowerUpId
+	PlayerId  types.PlayerId
+	Kind      types.PowerUpKind
+}
+
+// Message periodically sent from the server to the clients with the time left
+// before the match ends.
+type MatchTimer struct {
+	Remaining time.Duration
+}
+
+// Message sent from the server to the clients once the match is over, along
+// with the final scores.
+type MatchEnded struct {
+	PlayerData []PlayerData
+
+	// How long until the next round starts in the same arena, 0 when the
+	// players go back to the lobby instead.
+	NextRoundIn time.Duration
+}
+
+// Message sent from the server to the clients when a new round starts, with
+// every ship back in one piece. Bullets, asteroids and power-ups are gone, the
+// asteroids and power-ups of the new round are spawned afresh.
+type RoundReset struct {
+	PlayerData []PlayerData
+}
+
+// Message sent from the client to the server instead of a handshake to ask
+// for the all-time leaderboard.
+type RequestLeaderboard struct{}
+
+// The stats of a player across every match played on the server.
+type PlayerStats struct {
+	PlayerName string
+	Kills      int
+	Deaths     int
+	Wins       int
+}
+
+// Message sent from the server to the client with the best players of all
+// time, after which the connection is closed.
+type LeaderboardData struct {
+	Entries []PlayerStats
+}
+
+// Message sent from the client to the server when the game window gains or
+// loses focus.
+type UpdateFocus struct {
+	IsFocused bool
+}
+
+// Message sent from the server to the clients when a practice game against
+// bots is paused or resumed.
+type EventGamePaused struct {
+	IsPaused bool
+}
@@ -0,0 +1,106 @@
+package messages
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+)
+
+// Message periodically sent from the server instead of a `WorldSnapshot` to
+// clients that acknowledged a recent snapshot, holding only what changed
+// for the players since that baseline. Asteroids, power-ups and missiles are
+// always sent whole.
+type WorldSnapshotDelta struct {
+	Sequence uint32
+	Baseline uint32
+
+	Players        []PlayerDelta
+	RemovedPlayers []types.PlayerId
+
+	Asteroids []AsteroidData
+	PowerUps  []PowerUpData
+	Missiles  []MissileData
+}
+
+// Message sent from the client to the server with the sequence of the last
+// snapshot it applied, which later deltas are then based on.
+type AcknowledgeSnapshot struct {
+	Sequence uint32
+}
+
+// The fields of a player that changed since the baseline, the ones left nil
+// stayed the same.
+type PlayerDelta struct {
+	PlayerId types.PlayerId
+
+	PlayerName     *string                 `msgpack:",omitempty"`
+	Team           *types.Team             `msgpack:",omitempty"`
+	Class          *types.ShipClass        `msgpack:",omitempty"`
+	Weapon         *types.WeaponId         `msgpack:",omitempty"`
+	Position       *component.PositionData `msgpack:",omitempty"`
+	Health         *float64                `msgpack:",omitempty"`
+	Shield         *float64                `msgpack:",omitempty"`
+	Score          *int                    `msgpack:",omitempty"`
+	Lives          *int                    `msgpack:",omitempty"`
+	IsAlive        *bool                   `msgpack:",omitempty"`
+	IsConnected    *bool                   `msgpack:",omitempty"`
+	IsReady        *bool                   `msgpack:",omitempty"`
+	IsInvulnerable *bool                   `msgpack:",omitempty"`
+	LastSequence   *uint32                 `msgpack:",omitempty"`
+}
+
+// Returns the fields that differ between the baseline and the current state
+// of a player, and whether there were any.
+func DiffPlayerData(baseline PlayerData, current PlayerData) (PlayerDelta, bool) {
+	delta := PlayerDelta{PlayerId: current.PlayerId}
+	changed := false
+
+	diff(&delta.PlayerName, baseline.PlayerName, current.PlayerName, &changed)
+	diff(&delta.Team, baseline.Team, current.Team, &changed)
+	diff(&delta.Class, baseline.Class, current.Class, &changed)
+	diff(&delta.Weapon, baseline.Weapon, current.Weapon, &changed)
+	diff(&delta.Position, baseline.Position, current.Position, &changed)
+	diff(&delta.Health, baseline.Health, current.Health, &changed)
+	diff(&delta.Shield, baseline.Shield, current.Shield, &changed)
+	diff(&delta.Score, baseline.Score, current.Score, &changed)
+	diff(&delta.Lives, baseline.Lives, current.Lives, &changed)
+	diff(&delta.IsAlive, baseline.IsAlive, current.IsAlive, &changed)
+	diff(&delta.IsConnected, baseline.IsConnected, current.IsConnected, &changed)
+	diff(&delta.IsReady, baseline.IsReady, current.IsReady, &changed)
+	diff(&delta.IsInvulnerable, baseline.IsInvulnerable, current.IsInvulnerable, &changed)
+	diff(&delta.LastSequence, baseline.LastSequence, current.LastSequence, &changed)
+
+	return delta, changed
+}
+
+// Overwrites the fields of the player that changed.
+func (self *PlayerDelta) ApplyTo(data *PlayerData) {
+	data.PlayerId = self.PlayerId
+
+	apply(&data.PlayerName, self.PlayerName)
+	apply(&data.Team, self.Team)
+	apply(&data.Class, self.Class)
+	apply(&data.Weapon, self.Weapon)
+	apply(&data.Position, self.Position)
+	apply(&data.Health, self.Health)
+	apply(&data.Shield, self.Shield)
+	apply(&data.Score, self.Score)
+	apply(&data.Lives, self.Lives)
+	apply(&data.IsAlive, self.IsAlive)
+	apply(&data.IsConnected, self.IsConnected)
+	apply(&data.IsReady, self.IsReady)
+	apply(&data.IsInvulnerable, self.IsInvulnerable)
+	apply(&data.LastSequence, self.LastSequence)
+}
+
+func diff[T comparable](field **T, baseline T, current T, changed *bool) {
+	if baseline != current {
+		*field = &current
+		*changed = true
+	}
+}
+
+func apply[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
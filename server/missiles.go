@@ -0,0 +1,27 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/server/messages"
+
+	"github.com/yohamta/donburi"
+)
+
+func (self *Server) getMissileData() []messages.MissileData {
+	missileData := []messages.MissileData{}
+	query := donburi.NewQuery(component.Active(component.Bullet))
+
+	for bullet := range query.Iter(self.simulation.ECS.World) {
+		data := component.Bullet.Get(bullet)
+		if data.MissileId == types.InvalidMissileId {
+			continue
+		}
+		missileData = append(missileData, messages.MissileData{
+			MissileId: data.MissileId,
+			Position:  *component.Position.Get(bullet),
+			TargetId:  data.TargetId,
+		})
+	}
+	return missileData
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"time"
+)
+
+// Whether the only player in the game is practicing against bots, nobody else
+// minds the game being paused then.
+func (self *Server) isPractice() bool {
+	if len(self.bots) == 0 {
+		return false
+	}
+
+	players := 0
+	for _, connection := range self.players {
+		if connection.isConnected && !connection.isSpectator {
+			players += 1
+		}
+	}
+	return players == 1
+}
+
+// Pauses or resumes the game, pausing is refused outside of practice games.
+func (self *Server) setPaused(isPaused bool) {
+	if isPaused == self.isPaused || (isPaused && !self.isPractice()) {
+		return
+	}
+	self.isPaused = isPaused
+
+	if isPaused {
+		self.pausedWhen = time.Now()
+	} else if !self.matchEndsWhen.IsZero() {
+		// The match clock doesn't run while paused.
+		self.matchEndsWhen = self.matchEndsWhen.Add(time.Since(self.pausedWhen))
+	}
+
+	self.logger.Debug("Updated the pause", "isPaused", isPaused)
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventGamePaused{IsPaused: isPaused}))
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"math/rand"
+	"time"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+const (
+	maxPowerUps          = 5
+	powerUpSpawnInterval = 10 * time.Second
+)
+
+// Spawns a new power-up every once in a while until the map has enough of them.
+func (self *Server) spawnPowerUps() {
+	count := donburi.NewQuery(filter.Contains(component.PowerUp)).Count(self.simulation.ECS.World)
+	if count >= maxPowerUps || time.Since(self.lastPowerUpSpawned) < powerUpSpawnInterval {
+		return
+	}
+	self.lastPowerUpSpawned = time.Now()
+
+	powerUpId := self.nextPowerUpId
+	self.nextPowerUpId++
+
+	kind := types.PowerUpKind(rand.Intn(int(types.PowerUpKindCount)))
+	position := self.simulation.GenerateRandomPowerUpPosition()
+	self.simulation.CreatePowerUp(powerUpId, &position, kind)
+
+	self.broadcastMessage(rpc.NewBaseMessage(messages.SpawnPowerUp{
+		PowerUp: messages.PowerUpData{
+			PowerUpId: powerUpId,
+			Kind:      kind,
+			Position:  position,
+		},
+	}))
+}
+
+func (self *Server) onPowerUpCollect(player *donburi.Entry, powerUp *donburi.Entry) {
+	powerUpData := component.PowerUp.Get(powerUp)
+	self.simulation.ApplyPowerUp(player, powerUpData.Kind)
+
+	self.broadcastMessage(rpc.NewBaseMessage(messages.PowerUpCollected{
+		PowerUpId: powerUpData.Id,
+		PlayerId:  component.Player.Get(player).Id,
+		Kind:      powerUpData.Kind,
+	}))
+}
+
+func (self *Server) getPowerUpData() []messages.PowerUpData {
+	powerUpData := []messages.PowerUpData{}
+	query := donburi.NewQuery(filter.Contains(component.PowerUp, component.Position))
+
+	for powerUp := range query.Iter(self.simulation.ECS.World) {
+		data := component.PowerUp.Get(powerUp)
+		powerUpData = append(powerUpData, messages.PowerUpData{
+			PowerUpId: data.Id,
+			Kind:      data.Kind,
+			Position:  *component.Position.Get(powerUp),
+		})
+	}
+	return powerUpData
+}
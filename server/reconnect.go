@@ -0,0 +1,73 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/yohamta/donburi"
+)
+
+const (
+	// How long the ship of a player that lost its connection waits for them
+	// to come back, it is removed from the game afterwards.
+	reconnectTimeout = 30 * time.Second
+
+	// The least time between two reconnections of the same player, so that a
+	// flapping connection cannot keep taking the ship back over.
+	reconnectCooldown = time.Second
+)
+
+// Returns a token that can't be guessed, which the player presents to take
+// their ship back after losing the connection.
+func newReconnectToken() string {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(token)
+}
+
+// Returns the ship left behind by the player holding the token, if it is still
+// waiting for them.
+func (self *Server) reclaimShip(token string) (types.PlayerId, *donburi.Entry, bool) {
+	if token == "" {
+		return types.InvalidPlayerId, nil, false
+	}
+
+	for playerId, playerConn := range self.players {
+		if playerConn.reconnectToken != token {
+			continue
+		}
+		if playerConn.isConnected || playerConn.isBot {
+			return types.InvalidPlayerId, nil, false
+		}
+		if time.Since(playerConn.disconnectedWhen) > reconnectTimeout || time.Since(playerConn.reconnectedWhen) < reconnectCooldown {
+			return types.InvalidPlayerId, nil, false
+		}
+
+		player := self.simulation.FindCorrespondingPlayer(playerId)
+		if player == nil {
+			return types.InvalidPlayerId, nil, false
+		}
+		return playerId, player, true
+	}
+	return types.InvalidPlayerId, nil, false
+}
+
+// Removes the ships of the players that did not come back in time, along with
+// their tokens.
+func (self *Server) expireReconnectTokens() {
+	for playerId, playerConn := range self.players {
+		if playerConn.isConnected || playerConn.reconnectToken == "" || time.Since(playerConn.disconnectedWhen) <= reconnectTimeout {
+			continue
+		}
+
+		playerConn.reconnectToken = ""
+		if player := self.simulation.FindCorrespondingPlayer(playerId); player != nil {
+			self.simulation.QueueRemoval(player)
+		}
+		self.logger.Debug("Reconnect token expired", "player", playerId)
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"time"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Whether a match just ended and the next round is about to start.
+func (self *Server) isBetweenRounds() bool {
+	return self.roundTimer != nil
+}
+
+// Starts the next round once the break after the match is over, the players
+// stay connected in the meantime.
+func (self *Server) scheduleNextRound() {
+	self.roundTimer = time.AfterFunc(self.config.RoundBreak, func() {
+		self.mutex.Lock()
+		defer self.mutex.Unlock()
+		self.startRound()
+	})
+}
+
+// Clears the map and brings every ship back for a new round.
+func (self *Server) startRound() {
+	self.roundTimer = nil
+	if !self.hasGameStarted {
+		return
+	}
+
+	self.simulation.ClearWorld()
+	self.lastPowerUpSpawned = time.Now()
+
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		playerData.Lives = self.config.Lives
+		playerData.ClearPowerUps()
+		if !self.config.KeepScores {
+			playerData.Score = 0
+		}
+
+		// Ships that died after disconnecting stay dead until their player
+		// is back.
+		if !playerData.IsAlive && !playerData.IsConnected {
+			continue
+		}
+		self.simulation.RespawnPlayer(player, self.spawnPosition())
+		self.protect(player)
+	}
+
+	self.logger.Info("Round started", "duration", self.config.MatchDuration)
+	if self.config.MatchDuration > 0 {
+		self.matchEndsWhen = time.Now().Add(self.config.MatchDuration)
+	}
+	self.broadcastMessage(rpc.NewBaseMessage(messages.RoundReset{
+		PlayerData: self.getPlayerData(),
+	}))
+}
@@ -2,18 +2,23 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"astro-blasters/game"
 	"astro-blasters/game/component"
 	"astro-blasters/game/types"
 	"astro-blasters/rpc"
+	"astro-blasters/server/config"
 	"astro-blasters/server/messages"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/coder/websocket"
@@ -21,117 +26,329 @@ import (
 	"github.com/yohamta/donburi/filter"
 )
 
+// The maximum number of characters kept from a chat message.
+const maxChatMessageLength = 100
+
 type Server struct {
 	serveMux   http.ServeMux
 	simulation *game.GameSimulation
+	config     *config.ServerConfig
+	logger     *slog.Logger
+
+	leaderboard *leaderboard
+
+	// Guards the simulation and everything below, which the tick loop, the
+	// connections and the timers all touch. Writes to the connections happen
+	// in the background without it.
+	mutex sync.Mutex
 
 	players map[types.PlayerId]*playerConnection
+
+	// Players wait in the lobby until the game starts.
+	hasGameStarted bool
+	lobbyTimer     *time.Timer
+	matchEndsWhen  time.Time
+
+	// Set between the end of a match and the start of the next round.
+	roundTimer *time.Timer
+
+	nextAsteroidId types.AsteroidId
+
+	// Starts at 1 since 0 marks bullets that aren't missiles.
+	nextMissileId types.MissileId
+
+	nextPowerUpId      types.PowerUpId
+	lastPowerUpSpawned time.Time
+
+	bots []*bot
+
+	// Practice games against bots pause while the player looks away.
+	isPaused   bool
+	pausedWhen time.Time
+
+	snapshots snapshotHistory
 }
 
 type playerConnection struct {
-	mutex          sync.Mutex
-	conn           *websocket.Conn
-	isConnected    bool
-	lastBulletFire time.Time
+	conn        *websocket.Conn
+	isConnected bool
+	isReady     bool
+	isSpectator bool
+
+	// Bots are driven by the server, there is no connection behind them.
+	isBot bool
+
+	// Set during the handshake, large messages are compressed for this
+	// player if so.
+	supportsCompression bool
+
+	// The messages waiting to be written to the connection.
+	outbox chan rpc.EncodedMessage
+
+	// The sequence of the last move received from the player.
+	lastSequence uint32
+
+	// When we last received anything from the player, in unix nanoseconds.
+	lastSeen atomic.Int64
+
+	// The round trip time last reported by the player, in nanoseconds.
+	roundTripTime atomic.Int64
+
+	// The sequence of the last snapshot the player applied, which deltas are
+	// based on. 0 until the first one is acknowledged.
+	acknowledgedSnapshot atomic.Uint32
+
+	// Where the ship of the player was over the last few ticks.
+	history positionHistory
+
+	// Presented by the player to take their ship back after losing the
+	// connection, until `reconnectTimeout` after they disconnected.
+	reconnectToken   string
+	disconnectedWhen time.Time
+	reconnectedWhen  time.Time
 }
 
-func NewServer() *Server {
-	s := &Server{}
+func NewServer(config *config.ServerConfig) *Server {
+	s := &Server{config: config, logger: config.Logger}
+	s.leaderboard = loadLeaderboard(config.LeaderboardPath, s.logger)
 	s.players = make(map[types.PlayerId]*playerConnection)
 
 	s.serveMux.HandleFunc("/play/ws", s.ws)
 	s.serveMux.Handle("/", http.FileServer(http.Dir("server/static/")))
 
 	s.simulation = game.NewGameSimulation()
+	s.simulation.WorldMode = config.WorldMode
+	s.simulation.MapWidth = config.MapWidth
+	s.simulation.MapHeight = config.MapHeight
+	if config.Weapons != nil {
+		s.simulation.Weapons = config.Weapons
+	}
 
 	s.simulation.OnBulletCollide = s.onBulletCollide
 	s.simulation.OnBulletFire = s.onBulletFire
+	s.simulation.OnAsteroidCollide = s.onAsteroidCollide
+	s.simulation.OnPowerUpCollect = s.onPowerUpCollect
+	s.simulation.RewindPlayer = s.rewindPlayer
+	s.simulation.RewindDistance = maxRewindDistance
+	s.nextMissileId = 1
+
+	for i := range config.Bots {
+		s.spawnBot(i)
+	}
 	return s
 }
 
 func (self *Server) onBulletFire(player *donburi.Entry) {
-	playerId := component.Player.Get(player).Id
-	connection := self.players[playerId]
+	playerData := component.Player.Get(player)
 	now := time.Now()
 
-	if connection.lastBulletFire.IsZero() || now.Sub(connection.lastBulletFire) >= 300*time.Millisecond {
-		connection.lastBulletFire = now
-		self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerFireBullet{
-			PlayerId: playerId,
-		}))
-		self.simulation.RegisterPlayerFire(player)
+	weapon := self.simulation.GetWeaponStats(playerData.EquippedWeapon())
+	cooldown := weapon.Cooldown
+	if playerData.HasRapidFire() {
+		cooldown = min(cooldown, self.config.RapidFireCooldown)
+	}
+	cooldown = time.Duration(float64(cooldown) * game.GetShipStats(playerData.Class).FireCooldownScale)
+
+	// Drop the shot if the player is still cooling down.
+	if now.Sub(playerData.LastFired) < cooldown {
+		return
+	}
+
+	playerData.LastFired = now
+
+	// The server decides where a stray shot goes so that everyone sees it go
+	// the same way.
+	shot := game.Shot{
+		Angle:    game.GetFireAngle(player) + (rand.Float64()*2-1)*weapon.Spread,
+		TargetId: types.InvalidPlayerId,
+	}
+
+	// Missiles lock on to whoever is closest when fired, every client steers
+	// them the same way and the snapshots correct the rest.
+	if weapon.TurnRate > 0 {
+		shot.TargetId = self.simulation.FindMissileTarget(player)
+		shot.MissileId = self.nextMissileId
+		self.nextMissileId += types.MissileId(max(weapon.Pellets, 1))
 	}
+
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerFireBullet{
+		PlayerId:  playerData.Id,
+		Angle:     shot.Angle,
+		TargetId:  shot.TargetId,
+		MissileId: shot.MissileId,
+	}))
+	self.simulation.FireShot(player, shot)
 }
 
 func (self *Server) onBulletCollide(player *donburi.Entry, bullet *donburi.Entry) {
+	bulletData := component.Bullet.Get(bullet)
+	self.damagePlayer(player, bulletData.FiredBy, bulletData.DamageDealt())
+}
+
+// Damages the player and tells everyone about it, the attacker is
+// `types.InvalidPlayerId` when the damage did not come from another player.
+func (self *Server) damagePlayer(player *donburi.Entry, attackerId types.PlayerId, damage float64) {
 	playerData := component.Player.Get(player)
-	playerData.Health -= game.PlayerDamagePerHit
+
+	// Ships can only die once, whatever else hit them this tick, and fresh
+	// ships are protected for a while.
+	if !playerData.IsAlive || playerData.IsInvulnerable() {
+		return
+	}
+
+	playerData.TakeDamage(damage)
+	game.SetPlayerHealth(playerData, playerData.Health)
 
 	if playerData.Health > 0 {
-		self.broadcastMessage(rpc.NewBaseMessage(messages.EventUpdateHealth{
-			PlayerId: playerData.Id,
-			Health:   playerData.Health,
+		self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerHit{
+			PlayerId:   playerData.Id,
+			AttackerId: attackerId,
+			Health:     playerData.Health,
+			Shield:     playerData.Shield,
 		}))
-	} else if playerData.Health == 0 {
-		bulletData := component.Bullet.Get(bullet)
-		scorer := self.simulation.FindCorrespondingPlayer(bulletData.FiredBy)
+		return
+	}
 
-		scorerData := component.Player.Get(scorer)
+	scorer := self.simulation.FindCorrespondingPlayer(attackerId)
 
-		self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerDied{
-			PlayerId: playerData.Id,
-			KilledBy: scorerData.Id,
-		}))
+	killerName := ""
+	if scorer != nil {
+		killerName = component.Player.Get(scorer).Name
+	}
+	self.leaderboard.recordKill(killerName, playerData.Name)
 
-		self.simulation.RegisterPlayerDeath(player, scorer)
+	isEliminated := self.loseLife(player)
+	self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerDied{
+		PlayerId: playerData.Id,
+		KilledBy: attackerId,
+		Lives:    playerData.Lives,
+	}))
 
-		go func() {
-			time.Sleep(5 * time.Second)
-			position := game.GenerateRandomPlayerPosition()
-			self.simulation.RespawnPlayer(player, position)
+	self.simulation.RegisterPlayerDeath(player, scorer)
 
-			self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerRespawned{
-				PlayerId: playerData.Id,
-				Position: position,
-			}))
-		}()
+	// Eliminated players watch the rest of the match.
+	if isEliminated {
+		self.logger.Info("Player eliminated", "player", playerData.Id)
+		return
 	}
+
+	time.AfterFunc(game.RespawnDelay, func() {
+		self.mutex.Lock()
+		defer self.mutex.Unlock()
+
+		// A new round may have brought the ship back already, or the player
+		// gave it up to spectate.
+		if !player.Valid() || playerData.IsAlive {
+			return
+		}
+
+		position := self.spawnPosition()
+		self.simulation.RespawnPlayer(player, position)
+		self.protect(player)
+
+		self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerRespawned{
+			PlayerId: playerData.Id,
+			Position: position,
+		}))
+	})
 }
 
 func (self *Server) Start(port int) error {
-	fmt.Printf("Server started at %s:%d\n", getLocalIP(), port)
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return fmt.Errorf("Failed to listen on port %d: %w", port, err)
+	}
 
+	scheme := "http"
+	if self.config.IsSecure() {
+		scheme = "https"
+	}
+	self.logger.Info("Server started", "address", fmt.Sprintf("%s://%s:%d", scheme, getLocalIP(), port))
+	return self.Serve(listener)
+}
+
+// Runs the game and serves the connections accepted by the listener, so that
+// the server can also run in-process on a listener picked by the caller.
+func (self *Server) Serve(listener net.Listener) error {
 	go self.updateState()
 
-	return http.ListenAndServe(fmt.Sprintf(":%d", port), &self.serveMux)
+	if self.config.IsSecure() {
+		return http.ServeTLS(listener, &self.serveMux, self.config.TLSCertFile, self.config.TLSKeyFile)
+	}
+	return http.Serve(listener, &self.serveMux)
 }
 
 func (self *Server) ws(w http.ResponseWriter, r *http.Request) {
 	connection, err := websocket.Accept(w, r, nil)
 	if err != nil {
+		self.logger.Warn("Failed to accept a connection", "remote", r.RemoteAddr, "err", err)
 		fmt.Fprintf(w, "Connection Failed")
 		return
 	}
 
-	self.handleConnection(connection)
+	if err := self.handleConnection(connection); err != nil {
+		self.logger.Info("Connection ended", "remote", r.RemoteAddr, "err", err)
+	}
 }
 
 func (self *Server) handleConnection(connection *websocket.Conn) error {
 	ctx := context.Background()
-	// Register the connected player.
-	playerId, err := self.establishConnection(ctx, connection)
+
+	var first rpc.BaseMessage
+	if err := rpc.ReceiveMessage(ctx, connection, &first); err != nil {
+		return err
+	}
+
+	// Players looking at the leaderboard from the menu don't join the game.
+	if first.MessageType == "RequestLeaderboard" {
+		return self.sendLeaderboard(ctx, connection)
+	}
+
+	// Register the connected player, the handshake response is only queued
+	// so that nothing is written to the connection while everyone waits on
+	// the mutex.
+	self.mutex.Lock()
+	playerId, err := self.establishConnection(connection, first)
+	playerConn := self.players[playerId]
+	self.mutex.Unlock()
+
+	var rejection connectionRejection
+	if errors.As(err, &rejection) {
+		return self.rejectConnection(ctx, connection, rejection)
+	}
 	if err != nil {
 		return err
 	}
 
+	writerCtx, stopWriter := context.WithCancel(ctx)
+	go self.writeMessages(writerCtx, playerId, playerConn)
+
 	defer func() {
+		stopWriter()
 		connection.CloseNow()
-		player := self.simulation.FindCorrespondingPlayer(playerId)
-		self.simulation.RegisterPlayerDisconnection(player)
+
+		self.mutex.Lock()
+		defer self.mutex.Unlock()
+
+		// The player already reconnected on another connection.
+		if self.players[playerId].conn != connection {
+			return
+		}
+
 		self.players[playerId].isConnected = false
-		self.broadcastMessageExcept(playerId, rpc.NewBaseMessage(messages.EventPlayerDisconnected{
-			PlayerId: playerId,
-		}))
+		self.players[playerId].disconnectedWhen = time.Now()
+		self.logger.Info("Player disconnected", "player", playerId)
+
+		// Spectators have no ship to remove.
+		if player := self.simulation.FindCorrespondingPlayer(playerId); player != nil {
+			self.simulation.RegisterPlayerDisconnection(player)
+			self.broadcastMessageExcept(playerId, rpc.NewBaseMessage(messages.EventPlayerDisconnected{
+				PlayerId: playerId,
+			}))
+		}
+
+		// The players left in the lobby might all be ready by now.
+		self.startGameIfEveryoneIsReady()
 	}()
 
 	for {
@@ -143,141 +360,362 @@ func (self *Server) handleConnection(connection *websocket.Conn) error {
 			break
 		}
 
+		// A client on another version may send something we can't decode,
+		// which is no reason to drop it.
+		var decodeErr *rpc.DecodeError
+		if errors.As(err, &decodeErr) {
+			self.logger.Debug("Dropped a message", "player", playerId, "err", err)
+			continue
+		}
+
 		if err != nil {
 			break
 		}
 
-		switch message.MessageType {
-		case "RegisterPlayerMove":
-			var registerPlayerMove messages.RegisterPlayerMove
-			if err := rpc.DecodeExpectedMessage(message, &registerPlayerMove); err != nil {
-				continue
-			}
-			player := self.simulation.FindCorrespondingPlayer(playerId)
-			expectedPosition := component.Position.Get(player)
-
-			if !isPositionWithinTolerance(*expectedPosition, registerPlayerMove.Position, 3.0) {
-				self.broadcastMessage(rpc.NewBaseMessage(messages.UpdatePosition{
-					Position: *expectedPosition,
-					PlayerId: playerId,
-				}))
-			}
+		self.mutex.Lock()
+		self.handleMessage(playerId, message)
+		self.mutex.Unlock()
+	}
+	return nil
+}
+
+// Acts on a message received from the player.
+func (self *Server) handleMessage(playerId types.PlayerId, message rpc.BaseMessage) {
+	self.players[playerId].markSeen()
+
+	switch message.MessageType {
+	case "RegisterPlayerMove":
+		var registerPlayerMove messages.RegisterPlayerMove
+		if err := rpc.DecodeExpectedMessage(message, &registerPlayerMove); err != nil {
+			return
+		}
+		player := self.simulation.FindCorrespondingPlayer(playerId)
+
+		// Players cannot move while in the lobby, nor when dead until they
+		// are respawned. Spectators have no ship to move at all.
+		if player == nil || !self.hasGameStarted || !component.Player.Get(player).IsAlive {
+			return
+		}
+
+		self.players[playerId].lastSequence = registerPlayerMove.Sequence
 
-			self.simulation.RegisterPlayerMove(playerId, registerPlayerMove.Move)
-			self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerMove{
-				Move:     registerPlayerMove.Move,
+		// A move made from somewhere the ship isn't is dropped, and only the
+		// sender is told where their ship really is.
+		expectedPosition := component.Position.Get(player)
+		if !isPositionWithinTolerance(*expectedPosition, registerPlayerMove.Position, 3.0) {
+			self.sendMessage(playerId, self.players[playerId], rpc.NewBaseMessage(messages.UpdatePosition{
+				Position: *expectedPosition,
 				PlayerId: playerId,
+				Sequence: registerPlayerMove.Sequence,
 			}))
+			return
+		}
+
+		// The sender already applied the move on its own.
+		self.simulation.RegisterPlayerMove(playerId, registerPlayerMove.Move)
+		self.broadcastMessageExcept(playerId, rpc.NewBaseMessage(messages.EventPlayerMove{
+			Move:     registerPlayerMove.Move,
+			PlayerId: playerId,
+		}))
+	case "UpdateSteering":
+		var updateSteering messages.UpdateSteering
+		if err := rpc.DecodeExpectedMessage(message, &updateSteering); err != nil {
+			return
 		}
+		player := self.simulation.FindCorrespondingPlayer(playerId)
+		if player == nil || !self.hasGameStarted || !component.Player.Get(player).IsAlive {
+			return
+		}
+
+		self.simulation.RegisterPlayerSteering(playerId, updateSteering.IsSteering, updateSteering.Angle)
+		self.broadcastMessageExcept(playerId, rpc.NewBaseMessage(messages.EventPlayerSteering{
+			PlayerId:   playerId,
+			IsSteering: updateSteering.IsSteering,
+			Angle:      updateSteering.Angle,
+		}))
+	case "UpdateAim":
+		var updateAim messages.UpdateAim
+		if err := rpc.DecodeExpectedMessage(message, &updateAim); err != nil {
+			return
+		}
+		if player := self.simulation.FindCorrespondingPlayer(playerId); player != nil {
+			playerData := component.Player.Get(player)
+			playerData.IsAiming = updateAim.IsAiming
+			playerData.AimAngle = updateAim.Angle
+		}
+	case "UpdateFocus":
+		var updateFocus messages.UpdateFocus
+		if err := rpc.DecodeExpectedMessage(message, &updateFocus); err != nil {
+			return
+		}
+		self.setPaused(!updateFocus.IsFocused)
+	case "AcknowledgeSnapshot":
+		var acknowledgeSnapshot messages.AcknowledgeSnapshot
+		if err := rpc.DecodeExpectedMessage(message, &acknowledgeSnapshot); err != nil {
+			return
+		}
+		self.players[playerId].acknowledgedSnapshot.Store(acknowledgeSnapshot.Sequence)
+	case "Ping":
+		var ping messages.Ping
+		if err := rpc.DecodeExpectedMessage(message, &ping); err != nil {
+			return
+		}
+		self.players[playerId].roundTripTime.Store(int64(ping.RoundTripTime))
+		self.sendMessage(playerId, self.players[playerId], rpc.NewBaseMessage(messages.Pong{SentAt: ping.SentAt}))
+	case "JoinAsSpectator":
+		var joinAsSpectator messages.JoinAsSpectator
+		if err := rpc.DecodeExpectedMessage(message, &joinAsSpectator); err != nil {
+			return
+		}
+		self.joinAsSpectator(playerId)
+	case "RegisterPlayerReady":
+		var registerPlayerReady messages.RegisterPlayerReady
+		if err := rpc.DecodeExpectedMessage(message, &registerPlayerReady); err != nil {
+			return
+		}
+		self.registerPlayerReady(playerId, registerPlayerReady.IsReady)
+	case "ChatMessage":
+		var chatMessage messages.ChatMessage
+		if err := rpc.DecodeExpectedMessage(message, &chatMessage); err != nil {
+			return
+		}
+
+		text := strings.TrimSpace(chatMessage.Text)
+		if runes := []rune(text); len(runes) > maxChatMessageLength {
+			text = string(runes[:maxChatMessageLength])
+		}
+		if text == "" {
+			return
+		}
+
+		self.broadcastMessage(rpc.NewBaseMessage(messages.EventChatMessage{
+			PlayerId: playerId,
+			Text:     text,
+		}))
 	}
-	return nil
 }
 
 func isPositionWithinTolerance(expected component.PositionData, got component.PositionData, tolerance float64) bool {
-	return math.Pow(expected.X-got.X, 2)+math.Pow(expected.Y-got.Y, 2)+math.Pow(expected.Angle-got.Angle, 2) <= math.Pow(tolerance, 2)
+	return math.Pow(expected.X-got.X, 2)+math.Pow(expected.Y-got.Y, 2)+math.Pow(component.AngleDifference(expected.Angle, got.Angle), 2) <= math.Pow(tolerance, 2)
 }
 
 func (self *Server) updateState() {
-	ticker := time.NewTicker(time.Millisecond * 16) // ~60 FPS
+	ticker := time.NewTicker(time.Second / time.Duration(max(self.config.TickRate, 1)))
 	defer ticker.Stop()
 
-	for range ticker.C {
-		self.simulation.Update()
-	}
-}
+	snapshotTicker := time.NewTicker(time.Millisecond * 50) // 20 Hz
+	defer snapshotTicker.Stop()
 
-func (self *Server) sendMessage(playerId types.PlayerId, playerConn *playerConnection, message rpc.BaseMessage) {
-	// Lock the mutex to ensure only one goroutine writes at a time
-	playerConn.mutex.Lock()
-	defer playerConn.mutex.Unlock()
+	heartbeatTicker := time.NewTicker(time.Second)
+	defer heartbeatTicker.Stop()
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
+	lastUpdate := time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			self.mutex.Lock()
+			self.tick(now.Sub(lastUpdate).Seconds(), now)
+			self.mutex.Unlock()
+			lastUpdate = now
+		case <-snapshotTicker.C:
+			self.mutex.Lock()
+			self.sendSnapshots()
+			self.mutex.Unlock()
+		case <-heartbeatTicker.C:
+			self.mutex.Lock()
+			self.dropSilentPlayers()
+			self.expireReconnectTokens()
+			self.updateMatchTimer()
+			self.updateElimination()
+			self.mutex.Unlock()
+		}
+	}
+}
 
-	if !playerConn.isConnected {
+// Advances the game by `dt` seconds, unless it is paused.
+func (self *Server) tick(dt float64, now time.Time) {
+	// Someone joined or the player left, either way the game shouldn't stay
+	// paused.
+	if self.isPaused && !self.isPractice() {
+		self.setPaused(false)
+	}
+	if self.isPaused {
 		return
 	}
 
-	// Write the message to the connection
-	err := rpc.WriteMessage(ctx, playerConn.conn, message)
-	if err != nil {
-		log.Printf("Failed to send message to player %d: %v", playerId, err)
-	}
+	self.updateBots()
+	self.simulation.Update(dt)
+	self.recordPositions(now)
+	self.spawnAsteroids()
+	self.spawnPowerUps()
 }
 
-func (self *Server) broadcastMessage(message rpc.BaseMessage) {
-	for playerId, playerConn := range self.players {
-		go self.sendMessage(playerId, playerConn, message)
-	}
+func (self *Server) getAvailablePlayerId() types.PlayerId {
+	return types.PlayerId(len(self.players))
 }
 
-// For each playerid that does not match the sender, send the message.
-func (self *Server) broadcastMessageExcept(except types.PlayerId, message rpc.BaseMessage) {
-	for playerId, playerConn := range self.players {
-		if except == playerId {
-			continue
-		}
-		go self.sendMessage(playerId, playerConn, message)
-	}
+// The reason a player may not join, logged, along with the message told to
+// them once the server mutex is released.
+type connectionRejection struct {
+	reason  string
+	message any
 }
 
-func (self *Server) getAvailablePlayerId() types.PlayerId {
-	return types.PlayerId(len(self.players))
+func (self connectionRejection) Error() string {
+	return self.reason
 }
 
-func (self *Server) establishConnection(ctx context.Context, connection *websocket.Conn) (types.PlayerId, error) {
+func (self *Server) establishConnection(connection *websocket.Conn, message rpc.BaseMessage) (types.PlayerId, error) {
 	var connectionHandshake messages.ConnectionHandshake
-	if err := rpc.ReceiveExpectedMessage(ctx, connection, &connectionHandshake); err != nil {
-		return types.InvalidPlayerId, nil
+	if err := rpc.DecodeExpectedMessage(message, &connectionHandshake); err != nil {
+		return types.InvalidPlayerId, err
+	}
+
+	if connectionHandshake.ProtocolVersion != rpc.ProtocolVersion {
+		reason := fmt.Sprintf("Please update the game, the server uses protocol v%d but the game uses v%d", rpc.ProtocolVersion, connectionHandshake.ProtocolVersion)
+		return types.InvalidPlayerId, connectionRejection{reason: reason, message: messages.ConnectionRejected{Reason: reason}}
+	}
+
+	// Players that lost their connection take their ship back, which has to
+	// be left as it was.
+	playerId, player, isReconnect := self.reclaimShip(connectionHandshake.ReconnectToken)
+
+	if !isReconnect && !connectionHandshake.IsSpectator && self.isFull() {
+		return types.InvalidPlayerId, connectionRejection{reason: "The server is full", message: messages.ServerFull{MaxPlayers: self.config.MaxPlayers}}
 	}
 
-	playerId := self.getAvailablePlayerId()
-	position := game.GenerateRandomPlayerPosition()
+	position := self.spawnPosition()
+	if isReconnect {
+		playerData := component.Player.Get(player)
+		position = *component.Position.Get(player)
+		connectionHandshake.PlayerName = playerData.Name
+		connectionHandshake.ShipClass = playerData.Class
+		connectionHandshake.IsSpectator = false
+	} else {
+		playerId = self.getAvailablePlayerId()
+	}
 
+	// Fall back to a default name for players who did not type one.
+	connectionHandshake.PlayerName = strings.TrimSpace(connectionHandshake.PlayerName)
+	if connectionHandshake.PlayerName == "" {
+		connectionHandshake.PlayerName = fmt.Sprintf("Cadet %d", playerId)
+	}
+
+	isReady := isReconnect && self.players[playerId].isReady
 	self.players[playerId] = &playerConnection{
 		conn:        connection,
 		isConnected: true,
+		isSpectator: connectionHandshake.IsSpectator,
+		isReady:     isReady,
+
+		supportsCompression: connectionHandshake.SupportsCompression,
+		outbox:              make(chan rpc.EncodedMessage, outboxSize),
+		reconnectToken:      newReconnectToken(),
+	}
+	self.players[playerId].markSeen()
+
+	// Spectators watch the game without a ship of their own.
+	team := types.TeamNone
+	if isReconnect {
+		self.players[playerId].reconnectedWhen = time.Now()
+		team = component.Player.Get(player).Team
+		component.Player.Get(player).IsConnected = true
+	} else if !connectionHandshake.IsSpectator {
+		team = self.assignTeam()
+		player = self.simulation.CreatePlayer(playerId, &position, connectionHandshake.PlayerName, true, team, connectionHandshake.ShipClass)
+		component.Player.Get(player).Lives = self.config.Lives
+		self.protect(player)
 	}
 
-	self.simulation.CreatePlayer(playerId, &position, connectionHandshake.PlayerName, true)
+	// Queued before anything else is sent to the player, a failed write
+	// drops them like any other disconnection.
+	self.sendMessage(playerId, self.players[playerId], rpc.NewBaseMessage(messages.ConnectionHandshakeResponse{
+		PlayerId:       playerId,
+		PlayerData:     self.getPlayerData(),
+		HasGameStarted: self.hasGameStarted,
+		WorldMode:      self.config.WorldMode,
+		MapWidth:       self.simulation.MapWidth,
+		MapHeight:      self.simulation.MapHeight,
+		Weapons:        self.simulation.Weapons,
+		Lives:          self.config.Lives,
+		ReconnectToken: self.players[playerId].reconnectToken,
+	}))
 
-	playerData := self.getPlayerData()
-	err := rpc.WriteMessage(
-		ctx,
-		connection,
-		rpc.NewBaseMessage(messages.ConnectionHandshakeResponse{
-			PlayerId:   playerId,
-			PlayerData: playerData,
-		}),
-	)
+	self.logger.Info("Player connected", "player", playerId, "name", connectionHandshake.PlayerName, "spectator", connectionHandshake.IsSpectator, "team", team, "reconnected", isReconnect)
 
-	if err != nil {
-		log.Fatal(err)
+	if player == nil {
+		return playerId, nil
 	}
 
 	// Tell the other players that this player has joined.
 	self.broadcastMessageExcept(playerId, rpc.NewBaseMessage(messages.EventPlayerConnected{
 		PlayerId:   playerId,
 		PlayerName: connectionHandshake.PlayerName,
+		Team:       team,
+		Class:      connectionHandshake.ShipClass,
 		Position:   position,
 	}))
 
 	return playerId, nil
 }
 
+// Whether another ship would go past the player limit.
+func (self *Server) isFull() bool {
+	if self.config.MaxPlayers <= 0 {
+		return false
+	}
+
+	players := 0
+	for _, connection := range self.players {
+		if connection.isConnected && !connection.isSpectator {
+			players++
+		}
+	}
+	return players >= self.config.MaxPlayers
+}
+
+// Tells the client why it cannot join, then closes the connection.
+func (self *Server) rejectConnection(ctx context.Context, connection *websocket.Conn, rejection connectionRejection) error {
+	self.logger.Info("Rejected a connection", "reason", rejection.reason)
+
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	rpc.WriteMessage(ctx, connection, rpc.NewBaseMessage(rejection.message))
+
+	// Closing waits on the client to answer, which nobody needs to wait for.
+	go connection.Close(websocket.StatusPolicyViolation, "connection rejected")
+	return rejection
+}
+
 func (self *Server) getPlayerData() []messages.PlayerData {
 	enemyData := []messages.PlayerData{}
 	query := donburi.NewQuery(filter.Contains(component.Player, component.Position))
 
 	for player := range query.Iter(self.simulation.ECS.World) {
 		data := component.Player.Get(player)
+		if !data.IsConnected {
+			continue
+		}
 
 		enemyData = append(enemyData,
 			messages.PlayerData{
 				PlayerId:    data.Id,
 				PlayerName:  data.Name,
+				Team:        data.Team,
+				Class:       data.Class,
+				Weapon:      data.Weapon,
+				Health:      data.Health,
+				Shield:      data.Shield,
+				Score:       data.Score,
+				Lives:       data.Lives,
+				IsAlive:     data.IsAlive,
 				IsConnected: data.IsConnected,
-				Position:    *component.Position.Get(player),
+				IsReady:     self.isPlayerReady(data.Id),
+
+				IsInvulnerable: data.IsInvulnerable(),
+
+				LastSequence: self.players[data.Id].lastSequence,
+				Position:     *component.Position.Get(player),
 			},
 		)
 	}
@@ -302,3 +740,8 @@ func getLocalIP() string {
 
 	return ""
 }
+
+// Keeps the ship that just spawned from being damaged for a while.
+func (self *Server) protect(player *donburi.Entry) {
+	component.Player.Get(player).InvulnerableUntil = time.Now().Add(self.config.RespawnProtection)
+}
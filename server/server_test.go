@@ -0,0 +1,315 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/config"
+	"astro-blasters/server/messages"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/yohamta/donburi"
+)
+
+// How long a test waits for a message before giving up on it.
+const testTimeout = 5 * time.Second
+
+// Creates a server that logs nowhere and keeps its leaderboard in memory,
+// tweaked by `configure` before the server is created.
+func newTestServer(configure func(serverConfig *config.ServerConfig)) *Server {
+	serverConfig := config.DefaultServerConfig()
+	serverConfig.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	serverConfig.LeaderboardPath = ""
+	if configure != nil {
+		configure(&serverConfig)
+	}
+	return NewServer(&serverConfig)
+}
+
+// Adds a ship to the game without a connection behind it, like a bot, so
+// that nothing is sent anywhere.
+func addTestPlayer(server *Server, playerId types.PlayerId, position component.PositionData) *donburi.Entry {
+	server.players[playerId] = &playerConnection{}
+	return server.simulation.CreatePlayer(playerId, &position, fmt.Sprintf("Player %d", playerId), true, types.TeamNone, types.ShipFighter)
+}
+
+// Runs a server on a free port, returning it along with its websocket URL.
+// The config is tweaked by `configure` like in newTestServer.
+func startTestServer(t *testing.T, configure func(serverConfig *config.ServerConfig)) (*Server, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := newTestServer(configure)
+	go server.Serve(listener)
+	return server, fmt.Sprintf("ws://%s/play/ws", listener.Addr())
+}
+
+// A fake player talking to the server the way the game does.
+type testClient struct {
+	t        *testing.T
+	conn     *websocket.Conn
+	response messages.ConnectionHandshakeResponse
+}
+
+func connectTestClient(t *testing.T, url string, name string) *testClient {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect %s: %s", name, err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+
+	handshake := messages.ConnectionHandshake{ProtocolVersion: rpc.ProtocolVersion, PlayerName: name, SupportsCompression: true}
+	if err := rpc.WriteMessage(ctx, conn, rpc.NewBaseMessage(handshake)); err != nil {
+		t.Fatalf("Failed to send the handshake of %s: %s", name, err)
+	}
+
+	client := &testClient{t: t, conn: conn}
+	if err := rpc.ReceiveExpectedMessage(ctx, conn, &client.response); err != nil {
+		t.Fatalf("Failed to receive the handshake response of %s: %s", name, err)
+	}
+	return client
+}
+
+func (self *testClient) send(message any) {
+	self.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := rpc.WriteMessage(ctx, self.conn, rpc.NewBaseMessage(message)); err != nil {
+		self.t.Fatalf("Failed to send %T: %s", message, err)
+	}
+}
+
+// Reads messages until one of the type matches, skipping the snapshots,
+// timers and everything else the server sends in the meantime.
+func receive[Message any](client *testClient, matches func(Message) bool) Message {
+	client.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	var expected Message
+	for {
+		var message rpc.BaseMessage
+		if err := rpc.ReceiveMessage(ctx, client.conn, &message); err != nil {
+			client.t.Fatalf("Never received %T: %s", expected, err)
+		}
+
+		if rpc.DecodeExpectedMessage(message, &expected) == nil && matches(expected) {
+			return expected
+		}
+	}
+}
+
+// Waits for the simulation of the server to satisfy the condition.
+func waitForSimulation(t *testing.T, server *Server, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(testTimeout)
+	for time.Now().Before(deadline) {
+		server.mutex.Lock()
+		isSatisfied := condition()
+		server.mutex.Unlock()
+
+		if isSatisfied {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("The simulation never caught up")
+}
+
+// Where the game put the ship of the player when it started.
+func startPosition(t *testing.T, start messages.EventGameStart, playerId types.PlayerId) component.PositionData {
+	t.Helper()
+
+	for _, player := range start.PlayerData {
+		if player.PlayerId == playerId {
+			return player.Position
+		}
+	}
+	t.Fatalf("Player %d isn't in the game", playerId)
+	return component.PositionData{}
+}
+
+func TestTwoClients(t *testing.T) {
+	server, url := startTestServer(t, nil)
+
+	alice := connectTestClient(t, url, "Alice")
+	bob := connectTestClient(t, url, "Bob")
+	aliceId, bobId := alice.response.PlayerId, bob.response.PlayerId
+
+	// Bob learns about Alice from the handshake, Alice hears about Bob.
+	isAliceKnown := false
+	for _, player := range bob.response.PlayerData {
+		isAliceKnown = isAliceKnown || player.PlayerId == aliceId
+	}
+	if !isAliceKnown {
+		t.Errorf("Bob's handshake response doesn't include Alice")
+	}
+	connected := receive(alice, func(event messages.EventPlayerConnected) bool { return event.PlayerId == bobId })
+	if connected.PlayerName != "Bob" {
+		t.Errorf("Alice was told that %q connected, expected Bob", connected.PlayerName)
+	}
+
+	alice.send(messages.RegisterPlayerReady{IsReady: true})
+	bob.send(messages.RegisterPlayerReady{IsReady: true})
+	start := receive(alice, func(messages.EventGameStart) bool { return true })
+	receive(bob, func(messages.EventGameStart) bool { return true })
+
+	// Moves are only accepted from where the server has the ship, which
+	// stays at its spawn point until Alice thrusts.
+	position := startPosition(t, start, aliceId)
+	alice.send(messages.RegisterPlayerMove{Move: types.PlayerStartFireBullet, Position: position, Sequence: 1})
+	receive(bob, func(event messages.EventPlayerFireBullet) bool { return event.PlayerId == aliceId })
+	waitForSimulation(t, server, func() bool {
+		player := server.simulation.FindCorrespondingPlayer(aliceId)
+		return player != nil && !component.Player.Get(player).LastFired.IsZero()
+	})
+
+	alice.send(messages.RegisterPlayerMove{Move: types.PlayerStartForward, Position: position, Sequence: 2})
+	receive(bob, func(event messages.EventPlayerMove) bool {
+		return event.PlayerId == aliceId && event.Move == types.PlayerStartForward
+	})
+	waitForSimulation(t, server, func() bool {
+		player := server.simulation.FindCorrespondingPlayer(aliceId)
+		return player != nil && component.Player.Get(player).IsMovingForward
+	})
+}
+
+func TestMoveFromTheWrongPosition(t *testing.T) {
+	server, url := startTestServer(t, nil)
+
+	alice := connectTestClient(t, url, "Alice")
+	aliceId := alice.response.PlayerId
+	alice.send(messages.RegisterPlayerReady{IsReady: true})
+	receive(alice, func(messages.EventGameStart) bool { return true })
+
+	// No ship spawns off the map, the move has to be turned down.
+	alice.send(messages.RegisterPlayerMove{Move: types.PlayerStartForward, Position: component.PositionData{X: -1000, Y: -1000}, Sequence: 1})
+	correction := receive(alice, func(message messages.UpdatePosition) bool { return message.PlayerId == aliceId })
+	if correction.Sequence != 1 {
+		t.Errorf("The correction is for move %d, expected 1", correction.Sequence)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if player := server.simulation.FindCorrespondingPlayer(aliceId); component.Player.Get(player).IsMovingForward {
+		t.Errorf("The move was applied despite the correction")
+	}
+}
+
+func TestServerFull(t *testing.T) {
+	_, url := startTestServer(t, func(serverConfig *config.ServerConfig) { serverConfig.MaxPlayers = 1 })
+	connectTestClient(t, url, "Alice")
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect Bob: %s", err)
+	}
+	defer conn.CloseNow()
+
+	handshake := messages.ConnectionHandshake{ProtocolVersion: rpc.ProtocolVersion, PlayerName: "Bob"}
+	if err := rpc.WriteMessage(ctx, conn, rpc.NewBaseMessage(handshake)); err != nil {
+		t.Fatalf("Failed to send the handshake of Bob: %s", err)
+	}
+
+	var full messages.ServerFull
+	if err := rpc.ReceiveExpectedMessage(ctx, conn, &full); err != nil {
+		t.Fatalf("Bob wasn't told that the server is full: %s", err)
+	}
+	if full.MaxPlayers != 1 {
+		t.Errorf("Bob was told that the server takes %d players, expected 1", full.MaxPlayers)
+	}
+}
+
+func TestJoinAsSpectator(t *testing.T) {
+	server, url := startTestServer(t, nil)
+
+	alice := connectTestClient(t, url, "Alice")
+	aliceId := alice.response.PlayerId
+	alice.send(messages.JoinAsSpectator{})
+
+	// Alice is told too, so that her ship goes away on her end.
+	receive(alice, func(event messages.EventPlayerDisconnected) bool { return event.PlayerId == aliceId })
+	waitForSimulation(t, server, func() bool {
+		return server.simulation.FindCorrespondingPlayer(aliceId) == nil && server.players[aliceId].isSpectator
+	})
+}
+
+// Players joining and leaving all at once, run with -race to catch state
+// touched outside of the server mutex.
+func TestConcurrentJoinsAndLeaves(t *testing.T) {
+	server, url := startTestServer(t, nil)
+	watcher := connectTestClient(t, url, "Watcher")
+
+	var group sync.WaitGroup
+	for i := range 24 {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+			defer cancel()
+
+			conn, _, err := websocket.Dial(ctx, url, nil)
+			if err != nil {
+				t.Errorf("Failed to connect player %d: %s", i, err)
+				return
+			}
+			defer conn.CloseNow()
+
+			handshake := messages.ConnectionHandshake{ProtocolVersion: rpc.ProtocolVersion, PlayerName: fmt.Sprintf("Player %d", i), SupportsCompression: true}
+			if err := rpc.WriteMessage(ctx, conn, rpc.NewBaseMessage(handshake)); err != nil {
+				t.Errorf("Failed to send the handshake of player %d: %s", i, err)
+				return
+			}
+
+			// Past the player limit the server turns players away instead.
+			var response rpc.BaseMessage
+			if err := rpc.ReceiveMessage(ctx, conn, &response); err != nil {
+				t.Errorf("Player %d never heard back: %s", i, err)
+				return
+			}
+			if response.MessageType != "ConnectionHandshakeResponse" && response.MessageType != "ServerFull" {
+				t.Errorf("Player %d received %s instead of the handshake response", i, response.MessageType)
+				return
+			}
+
+			if response.MessageType == "ConnectionHandshakeResponse" {
+				rpc.WriteMessage(ctx, conn, rpc.NewBaseMessage(messages.RegisterPlayerReady{IsReady: true}))
+			}
+		}()
+	}
+	group.Wait()
+
+	waitForSimulation(t, server, func() bool {
+		for playerId, playerConn := range server.players {
+			if playerConn.isConnected && playerId != watcher.response.PlayerId {
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+)
+
+// How many snapshots deltas can be based on, a client that acknowledged an
+// older one is sent a full snapshot instead.
+const snapshotHistorySize = 32
+
+// The players of the last few snapshots sent, by sequence.
+type snapshotHistory struct {
+	sequence  uint32
+	sequences [snapshotHistorySize]uint32
+	players   [snapshotHistorySize][]messages.PlayerData
+}
+
+// Remembers the players of a new snapshot, returning its sequence. Sequences
+// start at 1 so that 0 never matches a snapshot.
+func (self *snapshotHistory) record(players []messages.PlayerData) uint32 {
+	self.sequence += 1
+
+	index := self.sequence % snapshotHistorySize
+	self.sequences[index] = self.sequence
+	self.players[index] = players
+	return self.sequence
+}
+
+func (self *snapshotHistory) find(sequence uint32) ([]messages.PlayerData, bool) {
+	index := sequence % snapshotHistorySize
+	if sequence == 0 || self.sequences[index] != sequence {
+		return nil, false
+	}
+	return self.players[index], true
+}
+
+// Sends every player the state of the world, as a delta from the last
+// snapshot they acknowledged when it is still known.
+func (self *Server) sendSnapshots() {
+	players := self.getPlayerData()
+	asteroids := self.getAsteroidData()
+	powerUps := self.getPowerUpData()
+	missiles := self.getMissileData()
+	sequence := self.snapshots.record(players)
+
+	full := rpc.NewBaseMessage(messages.WorldSnapshot{
+		Sequence:  sequence,
+		Players:   players,
+		Asteroids: asteroids,
+		PowerUps:  powerUps,
+		Missiles:  missiles,
+	})
+
+	// Players that acknowledged the same snapshot are sent the same delta,
+	// which is only encoded once. Full snapshots are stored under 0.
+	encoded := make(map[uint32]*broadcast)
+
+	for playerId, playerConn := range self.players {
+		if !playerConn.isConnected {
+			continue
+		}
+
+		acknowledged := playerConn.acknowledgedSnapshot.Load()
+		baseline, ok := self.snapshots.find(acknowledged)
+		if !ok {
+			acknowledged = 0
+		}
+
+		message, ok := encoded[acknowledged]
+		if !ok {
+			var err error
+			if acknowledged == 0 {
+				message, err = newBroadcast(full)
+			} else {
+				message, err = newBroadcast(rpc.NewBaseMessage(messages.WorldSnapshotDelta{
+					Sequence:       sequence,
+					Baseline:       acknowledged,
+					Players:        diffPlayers(baseline, players),
+					RemovedPlayers: removedPlayers(baseline, players),
+					Asteroids:      asteroids,
+					PowerUps:       powerUps,
+					Missiles:       missiles,
+				}))
+			}
+			if err != nil {
+				self.logger.Error("Failed to encode a snapshot", "sequence", sequence, "err", err)
+				return
+			}
+			encoded[acknowledged] = message
+		}
+		self.sendBroadcast(playerId, playerConn, message)
+	}
+}
+
+// Returns the changes of every player that is new or changed since the
+// baseline.
+func diffPlayers(baseline []messages.PlayerData, players []messages.PlayerData) []messages.PlayerDelta {
+	previous := make(map[types.PlayerId]messages.PlayerData, len(baseline))
+	for _, data := range baseline {
+		previous[data.PlayerId] = data
+	}
+
+	deltas := []messages.PlayerDelta{}
+	for _, data := range players {
+		// New players are diffed against a blank player, which is what the
+		// client starts them from.
+		if delta, changed := messages.DiffPlayerData(previous[data.PlayerId], data); changed {
+			deltas = append(deltas, delta)
+		}
+	}
+	return deltas
+}
+
+// Returns the players that were in the baseline but are gone now.
+func removedPlayers(baseline []messages.PlayerData, players []messages.PlayerData) []types.PlayerId {
+	current := make(map[types.PlayerId]bool, len(players))
+	for _, data := range players {
+		current[data.PlayerId] = true
+	}
+
+	removed := []types.PlayerId{}
+	for _, data := range baseline {
+		if !current[data.PlayerId] {
+			removed = append(removed, data.PlayerId)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+	"testing"
+)
+
+// Builds the full snapshot of a busy match: 32 players along with the
+// asteroids of a running game.
+func newTestSnapshot() rpc.BaseMessage {
+	server := newTestServer(nil)
+	for i := range 32 {
+		addTestPlayer(server, types.PlayerId(i), server.simulation.GenerateRandomPlayerPosition())
+	}
+	server.spawnAsteroids()
+
+	return rpc.NewBaseMessage(messages.WorldSnapshot{
+		Sequence:  1,
+		Players:   server.getPlayerData(),
+		Asteroids: server.getAsteroidData(),
+		PowerUps:  server.getPowerUpData(),
+		Missiles:  server.getMissileData(),
+	})
+}
+
+func BenchmarkCompressSnapshot(b *testing.B) {
+	snapshot := newTestSnapshot()
+
+	b.Run("Plain", func(b *testing.B) {
+		for range b.N {
+			if _, err := rpc.EncodeMessage(snapshot); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(len(snapshot.Payload)), "bytes/snapshot")
+	})
+
+	b.Run("Compressed", func(b *testing.B) {
+		var compressed rpc.BaseMessage
+		for range b.N {
+			compressed = rpc.CompressMessage(snapshot)
+			if _, err := rpc.EncodeMessage(compressed); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(len(compressed.Payload)), "bytes/snapshot")
+	})
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"astro-blasters/game"
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+	"astro-blasters/server/config"
+	"math"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// How many random spots the farthest strategy picks from.
+const spawnCandidates = 16
+
+// The fixed spawn points, as fractions of the map size.
+var spawnPoints = [][2]float64{
+	{0.1, 0.1}, {0.5, 0.1}, {0.9, 0.1},
+	{0.1, 0.5}, {0.9, 0.5},
+	{0.1, 0.9}, {0.5, 0.9}, {0.9, 0.9},
+}
+
+// Picks where a ship joining the game or respawning is placed, according to
+// the spawn strategy.
+func (self *Server) spawnPosition() component.PositionData {
+	switch self.config.SpawnStrategy {
+	case config.SpawnFarthest:
+		candidates := make([]component.PositionData, spawnCandidates)
+		for i := range candidates {
+			candidates[i] = self.simulation.GenerateRandomPlayerPosition()
+		}
+		return self.farthestFromShips(candidates)
+	case config.SpawnFixedPoints:
+		candidates := make([]component.PositionData, len(spawnPoints))
+		center := component.PositionData{X: self.simulation.MapWidth / 2, Y: self.simulation.MapHeight / 2}
+		for i, point := range spawnPoints {
+			candidates[i] = component.PositionData{X: point[0] * self.simulation.MapWidth, Y: point[1] * self.simulation.MapHeight}
+			// Face into the map rather than at the nearest wall.
+			candidates[i].Angle = component.WrapAngle(angleTowards(&candidates[i], &center))
+		}
+		return self.farthestFromShips(candidates)
+	default:
+		return self.simulation.GenerateRandomPlayerPosition()
+	}
+}
+
+// Returns the candidate whose closest ship is the furthest away.
+func (self *Server) farthestFromShips(candidates []component.PositionData) component.PositionData {
+	ships := []component.PositionData{}
+	for player := range donburi.NewQuery(filter.Contains(component.Player, component.Position)).Iter(self.simulation.ECS.World) {
+		data := component.Player.Get(player)
+		if data.IsAlive && data.IsConnected {
+			ships = append(ships, component.Position.GetValue(player))
+		}
+	}
+
+	best := candidates[0]
+	bestDistance := -1.0
+	for _, candidate := range candidates {
+		closest := math.Inf(1)
+		for _, ship := range ships {
+			closest = math.Min(closest, self.distance(&candidate, &ship))
+		}
+		if closest > bestDistance {
+			best, bestDistance = candidate, closest
+		}
+	}
+	return best
+}
+
+// The distance between two positions, the short way around when the map
+// wraps.
+func (self *Server) distance(a *component.PositionData, b *component.PositionData) float64 {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	if self.config.WorldMode == types.WorldWrap {
+		dx = game.WrapDelta(dx, self.simulation.MapWidth)
+		dy = game.WrapDelta(dy, self.simulation.MapHeight)
+	}
+	return math.Hypot(dx, dy)
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"astro-blasters/game/types"
+	"astro-blasters/rpc"
+	"astro-blasters/server/messages"
+)
+
+// Takes the ship away from the player, who watches the rest of the game like
+// the spectators do. The ship is gone for good, reconnecting doesn't bring it
+// back.
+func (self *Server) joinAsSpectator(playerId types.PlayerId) {
+	connection := self.players[playerId]
+	if connection.isSpectator {
+		return
+	}
+	connection.isSpectator = true
+	connection.isReady = false
+	self.logger.Info("Player joined the spectators", "player", playerId)
+
+	// The player is told too, so that their own ship goes away after every
+	// snapshot that still had it.
+	if player := self.simulation.FindCorrespondingPlayer(playerId); player != nil {
+		self.simulation.QueueRemoval(player)
+		self.broadcastMessage(rpc.NewBaseMessage(messages.EventPlayerDisconnected{
+			PlayerId: playerId,
+		}))
+	}
+
+	// The players left in the lobby might all be ready by now.
+	self.startGameIfEveryoneIsReady()
+}
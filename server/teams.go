@@ -0,0 +1,29 @@
+package server
+
+import (
+	"astro-blasters/game/component"
+	"astro-blasters/game/types"
+
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/filter"
+)
+
+// Puts the new player in the team with the fewest connected players.
+func (self *Server) assignTeam() types.Team {
+	if !self.config.TeamMode {
+		return types.TeamNone
+	}
+
+	counts := map[types.Team]int{}
+	for player := range donburi.NewQuery(filter.Contains(component.Player)).Iter(self.simulation.ECS.World) {
+		playerData := component.Player.Get(player)
+		if playerData.IsConnected {
+			counts[playerData.Team]++
+		}
+	}
+
+	if counts[types.TeamBlue] < counts[types.TeamRed] {
+		return types.TeamBlue
+	}
+	return types.TeamRed
+}